@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/evolve"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// championFlags are the flags seeding a new simulation with a genome saved by evolve, shared by commands.
+type championFlags struct {
+	path  *string
+	cells *int
+}
+
+// newChampionFlags registers champion flags in a flag set.
+func newChampionFlags(fs *flag.FlagSet) *championFlags {
+	return &championFlags{
+		path:  fs.String("champion", "", "JSON file of a champion saved by evolve, whose genome is carried by the initial cells"),
+		cells: fs.Int("champion-cells", 0, "number of initial cells carrying the champion genome, all of them if 0"),
+	}
+}
+
+// world creates a new world from the simulation flags, seeded with the champion genome if a champion file is set.
+func (f *championFlags) world(simulation *simulationFlags) (*sim.World, error) {
+	if *f.path == "" {
+		return simulation.world()
+	}
+	config, err := simulation.config()
+	if err != nil {
+		return nil, err
+	}
+	champion, err := evolve.LoadIndividual(*f.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Cell.CheckGenome(champion.Genome); err != nil {
+		return nil, err
+	}
+	n := *f.cells
+	if n <= 0 {
+		n = config.Population
+	}
+	genomes := make([]cell.Genome, n)
+	for i := range genomes {
+		genomes[i] = champion.Genome
+	}
+	log.Infof("golife seed: %d", *simulation.seed)
+	log.Infof("%d cells carry the champion of fitness %0.2f loaded from %s", n, champion.Fitness, *f.path)
+	return sim.NewWithGenomes(config, *simulation.seed, genomes), nil
+}
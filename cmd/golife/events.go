@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/jtbonhomme/golife/pkg/sim"
+	"github.com/sirupsen/logrus"
+)
+
+// eventsFlags are the flags describing the cell events export, shared by commands.
+type eventsFlags struct {
+	path *string
+	log  *bool
+}
+
+// newEventsFlags registers events flags in a flag set.
+func newEventsFlags(fs *flag.FlagSet) *eventsFlags {
+	return &eventsFlags{
+		path: fs.String("events", "", "file the births, deaths and meals of cells are written to, as JSON lines"),
+		log:  fs.Bool("log-events", false, "log the births, deaths and meals of cells"),
+	}
+}
+
+// eventLog enables the event logs, and opens the events file and returns an event log writing to it,
+// or a nil event log if no events file is set. The file must be closed by the caller.
+func (f *eventsFlags) eventLog() (*sim.EventLog, *os.File, error) {
+	if *f.log {
+		// events are emitted as debug logs by the simulation
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+	if *f.path == "" {
+		return nil, nil, nil
+	}
+	file, err := os.Create(*f.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Infof("cell events written to %s", *f.path)
+	return sim.NewEventLog(file), file, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/jtbonhomme/golife/pkg/evolve"
+)
+
+// evolveCommand runs a generational genetic algorithm experiment, and saves its champion.
+func evolveCommand(args []string) error {
+	fs := flag.NewFlagSet("evolve", flag.ExitOnError)
+	simulation := newSimulationFlags(fs)
+	defaults := evolve.DefaultConfig()
+	population := fs.Int("population", defaults.Population, "number of genomes of each generation")
+	generations := fs.Int("generations", defaults.Generations, "number of generations to evaluate")
+	ticks := fs.Int("ticks", defaults.Ticks, "length of an episode")
+	episodes := fs.Int("episodes", defaults.Episodes, "number of episodes each generation is evaluated in")
+	elites := fs.Int("elites", defaults.Elites, "number of best genomes copied unchanged to the next generation")
+	tournament := fs.Int("tournament", defaults.TournamentSize, "number of genomes competing to be selected as a parent")
+	crossover := fs.Float64("crossover", defaults.CrossoverRate, "probability for an offspring to mix two parents")
+	fitnessName := fs.String("fitness", "survival", "fitness of genomes: "+strings.Join(evolve.FitnessNames(), ", "))
+	champion := fs.String("champion", "champion.json", "JSON file the best genome is saved to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	world, err := simulation.config()
+	if err != nil {
+		return err
+	}
+	fitness, err := evolve.ParseFitness(*fitnessName)
+	if err != nil {
+		return err
+	}
+	config := evolve.Config{
+		Population:     *population,
+		Generations:    *generations,
+		Ticks:          *ticks,
+		Episodes:       *episodes,
+		Elites:         *elites,
+		TournamentSize: *tournament,
+		CrossoverRate:  *crossover,
+		World:          world,
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	log.Infof("golife seed: %d", *simulation.seed)
+	enc := json.NewEncoder(os.Stdout)
+	best := evolve.New(config, fitness, *simulation.seed).Run(func(g evolve.Generation) {
+		enc.Encode(struct {
+			Generation int     `json:"generation"`
+			Best       float64 `json:"best"`
+			Mean       float64 `json:"mean"`
+			Species    int     `json:"species,omitempty"`
+		}{g.Index, g.Best, g.Mean, g.Species})
+	})
+	if err := evolve.SaveIndividual(*champion, best); err != nil {
+		return err
+	}
+	log.Infof("champion of fitness %0.2f saved to %s", best.Fitness, *champion)
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+	"github.com/jtbonhomme/golife/pkg/neat"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// simulationFlags are the flags describing a new simulation, shared by commands.
+type simulationFlags struct {
+	fs         *flag.FlagSet
+	configPath *string
+	seed       *int64
+	width      *int
+	height     *int
+	population *int
+	spawnRate  *float64
+	spawnFloor *int
+	foodRate   *float64
+	foodMax    *int
+	collisions *bool
+	maxAge     *int
+	visionRays *int
+	fov        *float64
+	hidden     *string
+	activation *string
+	neat       *bool
+}
+
+// newSimulationFlags registers simulation flags in a flag set.
+func newSimulationFlags(fs *flag.FlagSet) *simulationFlags {
+	defaults := sim.DefaultConfig()
+	return &simulationFlags{
+		fs:         fs,
+		configPath: fs.String("config", "", "JSON, YAML or TOML file holding the simulation parameters, overridden by command line flags"),
+		seed:       fs.Int64("seed", time.Now().UnixNano(), "seed of the simulation random source"),
+		width:      fs.Int("width", defaults.Width, "world width"),
+		height:     fs.Int("height", defaults.Height, "world height"),
+		population: fs.Int("cells", defaults.Population, "initial number of cells"),
+		spawnRate:  fs.Float64("spawn-rate", defaults.Spawner.Rate, "probability for a new cell to be spontaneously generated at each tick"),
+		spawnFloor: fs.Int("spawn-floor", defaults.Spawner.Floor, "population under which new cells are spontaneously generated"),
+		foodRate:   fs.Float64("food-rate", defaults.Food.Rate, "average number of food pellets growing at each tick"),
+		foodMax:    fs.Int("food-max", defaults.Food.Max, "number of food pellets above which none grows anymore"),
+		collisions: fs.Bool("collisions", defaults.Collisions, "push apart cells overlapping each other"),
+		maxAge:     fs.Int("max-age", defaults.Cell.MaxAge, "number of ticks after which cells die of old age, never if 0"),
+		visionRays: fs.Int("vision-rays", defaults.Cell.VisionRays, "number of vision rays cast by cells"),
+		fov:        fs.Float64("vision-fov", defaults.Cell.VisionFieldOfView, "angle covered by cells vision rays (degree)"),
+		hidden:     fs.String("brain", "", "comma separated sizes of the cells neural network hidden layers, hard-coded behavior if empty"),
+		activation: fs.String("brain-activation", string(defaults.Cell.Brain.Activation), "activation of the cells neural network hidden layers"),
+		neat:       fs.Bool("neat", false, "drive cells by NEAT networks growing their topology, in place of fixed hidden layers"),
+	}
+}
+
+// config returns the simulation config read from the config file, if any,
+// overridden by the flags explicitly set on the command line.
+func (f *simulationFlags) config() (sim.Config, error) {
+	config := sim.DefaultConfig()
+	if *f.configPath != "" {
+		var err error
+		config, err = sim.LoadConfig(*f.configPath)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	var err error
+	keepFirst := func(e error) {
+		if err == nil {
+			err = e
+		}
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "width":
+			config.Width = *f.width
+		case "height":
+			config.Height = *f.height
+		case "cells":
+			config.Population = *f.population
+		case "spawn-rate":
+			config.Spawner.Rate = *f.spawnRate
+		case "spawn-floor":
+			config.Spawner.Floor = *f.spawnFloor
+		case "food-rate":
+			config.Food.Rate = *f.foodRate
+		case "food-max":
+			config.Food.Max = *f.foodMax
+		case "collisions":
+			config.Collisions = *f.collisions
+		case "max-age":
+			config.Cell.MaxAge = *f.maxAge
+		case "vision-rays":
+			config.Cell.VisionRays = *f.visionRays
+		case "vision-fov":
+			config.Cell.VisionFieldOfView = *f.fov
+		case "brain":
+			layers, e := parseLayers(*f.hidden)
+			config.Cell.Brain.Hidden = layers
+			keepFirst(e)
+		case "brain-activation":
+			a, e := brain.ParseActivation(*f.activation)
+			config.Cell.Brain.Activation = a
+			keepFirst(e)
+		case "neat":
+			// keep the NEAT parameters of the config file, if any
+			if !*f.neat {
+				config.Cell.Brain.NEAT = nil
+			} else if config.Cell.Brain.NEAT == nil {
+				c := neat.DefaultConfig()
+				config.Cell.Brain.NEAT = &c
+			}
+		}
+	})
+	if err != nil {
+		return config, err
+	}
+	return config, config.Validate()
+}
+
+// world creates a new world from the flags.
+func (f *simulationFlags) world() (*sim.World, error) {
+	config, err := f.config()
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("golife seed: %d", *f.seed)
+	return sim.New(config, *f.seed), nil
+}
+
+// parseLayers parses comma separated sizes of neural network hidden layers.
+func parseLayers(hidden string) ([]int, error) {
+	layers := []int{}
+	if hidden == "" {
+		return layers, nil
+	}
+	for _, field := range strings.Split(hidden, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, n)
+	}
+	return layers, nil
+}
+
+// restore creates a world from a snapshot file.
+func restore(path string) (*sim.World, error) {
+	s, err := sim.LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	world, err := sim.Restore(s)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("world restored from %s at tick %d", path, world.Counter())
+	return world, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// lineageFlags are the flags describing the lineage export, shared by commands.
+type lineageFlags struct {
+	path      *string
+	survivors *bool
+}
+
+// newLineageFlags registers lineage flags in a flag set.
+func newLineageFlags(fs *flag.FlagSet) *lineageFlags {
+	return &lineageFlags{
+		path:      fs.String("lineage", "", "file the cells ancestry is exported to at the end of the simulation, as GraphViz DOT if it ends with .dot, as Newick otherwise"),
+		survivors: fs.Bool("lineage-survivors", false, "only export the ancestry of the living cells"),
+	}
+}
+
+// save exports the ancestry of the world cells, if a lineage file is set.
+func (f *lineageFlags) save(world *sim.World) error {
+	if *f.path == "" {
+		return nil
+	}
+	lineage := world.Lineage()
+	if *f.survivors {
+		lineage = lineage.Survivors()
+	}
+	if err := lineage.Save(*f.path, world.Counter()); err != nil {
+		return err
+	}
+	log.Infof("lineage of %d cells exported to %s", len(lineage.Records()), *f.path)
+	return nil
+}
@@ -1,30 +1,58 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/sirupsen/logrus"
-
-	"github.com/jtbonhomme/golife/internal/version"
-	"github.com/jtbonhomme/golife/pkg/game"
 )
 
-const (
-	TileDimension int = 80
-	ScreenWidth   int = 16 * TileDimension // 1280
-	ScreenHeight  int = 9 * TileDimension  // 720
-)
+const usage = `golife is a cell evolution simulator.
+
+Usage:
+
+	golife <command> [flags] [arguments]
+
+Commands:
+
+	run              open a window and run a simulation (default)
+	sim              run a simulation without display for a given number of ticks
+	replay <file>    open a window and replay a recorded run, or resume a snapshot
+	evolve           evolve genomes with a genetic algorithm in headless episodes
+	version          print golife version as JSON
+
+Run "golife <command> -h" for the flags of a command.
+`
+
+var log = logrus.New()
 
 func main() {
-	log := logrus.New()
-	log.Infof("golife version: %#v", version.Read())
-	os.Setenv("EBITEN_SCREENSHOT_KEY", "s")
-	g := game.New(ScreenWidth, ScreenHeight, TileDimension)
-
-	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
-	ebiten.SetWindowTitle("golife (jtbonhomme@gmail.com)")
-	if err := ebiten.RunGame(g); err != nil {
+	args := os.Args[1:]
+	command := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "run":
+		err = runCommand(args)
+	case "sim":
+		err = simCommand(args)
+	case "replay":
+		err = replayCommand(args)
+	case "evolve":
+		err = evolveCommand(args)
+	case "version":
+		err = versionCommand(args)
+	case "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 }
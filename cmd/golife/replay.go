@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// replayResult describes a replay verified without display, up to its last checkpoint.
+type replayResult struct {
+	Tick        int `json:"tick"`
+	Checkpoints int `json:"checkpoints"`
+}
+
+// replayCommand opens a window and replays a run recorded with run -record, verifying its state hashes.
+// Snapshot files are resumed as is.
+func replayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	window := newWindowFlags(fs)
+	headless := fs.Bool("headless", false, "replay the run without display up to its last checkpoint, and print a JSON result")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: golife replay [flags] <file>\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("replay expects a replay or snapshot file")
+	}
+
+	r, err := sim.LoadReplay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if r.Initial == nil {
+		// not a replay: resume the snapshot
+		world, err := restore(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return runGame(newGame(world, window))
+	}
+	world, player, err := sim.NewPlayer(r)
+	if err != nil {
+		return err
+	}
+	log.Infof("replaying %s from tick %d: %d inputs, %d checkpoints", fs.Arg(0), world.Counter(), len(r.Inputs), len(r.Checkpoints))
+
+	if *headless {
+		for !player.Done() {
+			if err := player.Step(world); err != nil {
+				return err
+			}
+		}
+		return json.NewEncoder(os.Stdout).Encode(replayResult{
+			Tick:        world.Counter(),
+			Checkpoints: player.Checkpoints(),
+		})
+	}
+	g := newGame(world, window)
+	g.SetPlayer(player)
+	return runGame(g)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/golife/internal/version"
+	"github.com/jtbonhomme/golife/pkg/game"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// runCommand opens a window and runs a new simulation, or the one saved in a snapshot.
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	simulation := newSimulationFlags(fs)
+	window := newWindowFlags(fs)
+	load := fs.String("load", "", "snapshot file to restore the world from at startup")
+	record := fs.String("record", "", "file the run is recorded to when the window is closed, as JSON if it ends with .json")
+	checkpointEvery := fs.Int("checkpoint-every", 100, "number of ticks between two state hashes of the recorded run")
+	champion := newChampionFlags(fs)
+	statistics := newStatsFlags(fs)
+	lineage := newLineageFlags(fs)
+	events := newEventsFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var world *sim.World
+	var err error
+	if *load != "" {
+		world, err = restore(*load)
+	} else {
+		world, err = champion.world(simulation)
+	}
+	if err != nil {
+		return err
+	}
+	collector, file, err := statistics.collector()
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		defer file.Close()
+	}
+	eventLog, eventsFile, err := events.eventLog()
+	if err != nil {
+		return err
+	}
+	if eventsFile != nil {
+		defer eventsFile.Close()
+	}
+
+	g := newGame(world, window)
+	if collector != nil {
+		g.AddObserver(collector)
+		g.SetStepHook(collector.Collect)
+	}
+	if eventLog != nil {
+		g.AddObserver(eventLog)
+	}
+	var recorder *sim.Recorder
+	if *record != "" {
+		recorder = sim.NewRecorder(world, *checkpointEvery)
+		g.SetRecorder(recorder)
+	}
+	err = runGame(g)
+	if recorder != nil {
+		if e := recorder.Replay().Save(*record); e != nil {
+			log.Errorf("can not save replay: %s", e.Error())
+		} else {
+			log.Infof("run recorded to %s", *record)
+		}
+	}
+	if e := lineage.save(g.World()); e != nil {
+		log.Errorf("can not export lineage: %s", e.Error())
+	}
+	return err
+}
+
+// windowFlags are the flags describing the game window, shared by commands.
+type windowFlags struct {
+	snapshot *string
+	width    *int
+	height   *int
+}
+
+// newWindowFlags registers window flags in a flag set.
+func newWindowFlags(fs *flag.FlagSet) *windowFlags {
+	return &windowFlags{
+		snapshot: fs.String("snapshot", "golife.snapshot", "file the world is saved to with F5 and restored from with F9, as JSON if it ends with .json"),
+		width:    fs.Int("window-width", 0, "window width, the world width if 0"),
+		height:   fs.Int("window-height", 0, "window height, the world height if 0"),
+	}
+}
+
+// newGame creates a game rendering a world in a window described by flags.
+func newGame(world *sim.World, window *windowFlags) *game.Game {
+	g := game.NewWithWorld(world)
+	g.SetSnapshotPath(*window.snapshot)
+	width, height := *window.width, *window.height
+	if width <= 0 {
+		width = world.Width
+	}
+	if height <= 0 {
+		height = world.Height
+	}
+	g.SetScreenSize(width, height)
+	return g
+}
+
+// runGame opens a window running a game, until it is closed.
+func runGame(g *game.Game) error {
+	log.Infof("golife version: %#v", version.Read())
+	os.Setenv("EBITEN_SCREENSHOT_KEY", "s")
+	ebiten.SetWindowSize(g.ScreenWidth, g.ScreenHeight)
+	ebiten.SetWindowResizable(true)
+	ebiten.SetWindowTitle("golife (jtbonhomme@gmail.com)")
+	return ebiten.RunGame(g)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// summary describes the state of a headless simulation.
+type summary struct {
+	Seed       int64   `json:"seed"`
+	Tick       int     `json:"tick"`
+	Population int     `json:"population"`
+	Extinct    bool    `json:"extinct"`
+	Duration   float64 `json:"duration"`
+}
+
+// simCommand runs a simulation without display for a given number of ticks.
+func simCommand(args []string) error {
+	fs := flag.NewFlagSet("sim", flag.ExitOnError)
+	simulation := newSimulationFlags(fs)
+	ticks := fs.Int("ticks", 10000, "number of ticks to simulate")
+	logEvery := fs.Int("log-every", 1000, "number of ticks between two progress logs, never if 0")
+	load := fs.String("load", "", "snapshot file to restore the world from, instead of creating a new one")
+	snapshot := fs.String("snapshot", "", "file the world is saved to at the end of the simulation, as JSON if it ends with .json")
+	champion := newChampionFlags(fs)
+	statistics := newStatsFlags(fs)
+	lineage := newLineageFlags(fs)
+	events := newEventsFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var world *sim.World
+	var err error
+	if *load != "" {
+		world, err = restore(*load)
+	} else {
+		world, err = champion.world(simulation)
+	}
+	if err != nil {
+		return err
+	}
+	collector, file, err := statistics.collector()
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		defer file.Close()
+		world.AddObserver(collector)
+	}
+	eventLog, eventsFile, err := events.eventLog()
+	if err != nil {
+		return err
+	}
+	if eventsFile != nil {
+		defer eventsFile.Close()
+		world.AddObserver(eventLog)
+	}
+
+	start := time.Now()
+	extinct := false
+	for i := 0; i < *ticks; i++ {
+		if err := world.Step(); err != nil {
+			log.Warnf("simulation stopped at tick %d: %s", world.Counter(), err.Error())
+			extinct = true
+			break
+		}
+		if collector != nil {
+			if err := collector.Collect(world); err != nil {
+				return err
+			}
+		}
+		if eventLog != nil && eventLog.Err() != nil {
+			return eventLog.Err()
+		}
+		if *logEvery > 0 && world.Counter()%*logEvery == 0 {
+			log.Infof("tick %d: %d cells", world.Counter(), world.Population())
+		}
+	}
+
+	if *snapshot != "" {
+		if err := world.Snapshot().Save(*snapshot); err != nil {
+			return err
+		}
+		log.Infof("world saved to %s at tick %d", *snapshot, world.Counter())
+	}
+	if err := lineage.save(world); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	return enc.Encode(summary{
+		Seed:       world.Seed(),
+		Tick:       world.Counter(),
+		Population: world.Population(),
+		Extinct:    extinct,
+		Duration:   time.Since(start).Seconds(),
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/jtbonhomme/golife/pkg/stats"
+)
+
+// statsFlags are the flags describing the population statistics export, shared by commands.
+type statsFlags struct {
+	path  *string
+	every *int
+}
+
+// newStatsFlags registers statistics flags in a flag set.
+func newStatsFlags(fs *flag.FlagSet) *statsFlags {
+	return &statsFlags{
+		path:  fs.String("stats", "", "file population statistics are written to, as CSV if it ends with .csv, as JSON lines otherwise"),
+		every: fs.Int("stats-every", 1, "number of ticks between two population statistics samples"),
+	}
+}
+
+// collector opens the statistics file and returns a collector writing to it,
+// or a nil collector if no statistics file is set. The file must be closed by the caller.
+func (f *statsFlags) collector() (*stats.Collector, *os.File, error) {
+	if *f.path == "" {
+		return nil, nil, nil
+	}
+	file, err := os.Create(*f.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Infof("population statistics written to %s every %d ticks", *f.path, *f.every)
+	return stats.NewCollector(file, stats.FormatFromPath(*f.path), *f.every), file, nil
+}
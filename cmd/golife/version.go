@@ -0,0 +1,20 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/jtbonhomme/golife/internal/version"
+)
+
+// versionCommand prints golife version as JSON.
+func versionCommand(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(version.Read())
+}
@@ -2,17 +2,22 @@ module github.com/jtbonhomme/golife
 
 go 1.17
 
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/google/uuid v1.3.0
+	github.com/hajimehoshi/ebiten/v2 v2.2.3
+	github.com/lucasb-eyer/go-colorful v1.2.0
+	github.com/sirupsen/logrus v1.8.1
+	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20210727001814-0db043d8d5be // indirect
-	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
-	github.com/google/uuid v1.3.0 // indirect
 	github.com/hajimehoshi/ebiten v1.12.12 // indirect
-	github.com/hajimehoshi/ebiten/v2 v2.2.3 // indirect
 	github.com/jezek/xgb v0.0.0-20210312150743-0e0f116e1240 // indirect
-	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
-	github.com/sirupsen/logrus v1.8.1 // indirect
 	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
-	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d // indirect
 	golang.org/x/mobile v0.0.0-20210902104108-5d9a33257ab5 // indirect
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c // indirect
 	golang.org/x/sys v0.0.0-20210917161153-d61c044b1678 // indirect
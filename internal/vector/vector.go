@@ -82,7 +82,7 @@ func (v Vector2D) YSym(h float64) Vector2D {
 }
 
 func (v Vector2D) Distance(v2 Vector2D) float64 {
-	return math.Sqrt(math.Pow(v2.X-v.X, 2) + math.Pow(v2.Y-v.Y, 2))
+	return math.Sqrt(v.SquareDistance(v2))
 }
 
 func (v Vector2D) SquareDistance(v2 Vector2D) float64 {
@@ -96,3 +96,37 @@ func (v Vector2D) IsNil() bool {
 func (v Vector2D) IsEqual(v2 Vector2D) bool {
 	return (v.X == v2.X && v.Y == v2.Y)
 }
+
+// wrap returns the shortest signed difference d on a circle of length l.
+func wrap(d, l float64) float64 {
+	if l <= 0 {
+		return d
+	}
+	d = math.Mod(d, l)
+	if d > l/2 {
+		d -= l
+	} else if d < -l/2 {
+		d += l
+	}
+	return d
+}
+
+// TorusDisplacement returns the shortest vector from v to v2 on a torus of w x h,
+// where leaving an edge makes you enter back from the opposite one.
+func (v Vector2D) TorusDisplacement(v2 Vector2D, w, h float64) Vector2D {
+	return Vector2D{
+		X: wrap(v2.X-v.X, w),
+		Y: wrap(v2.Y-v.Y, h),
+	}
+}
+
+// TorusSquareDistance returns the squared shortest distance between v and v2 on a torus of w x h.
+func (v Vector2D) TorusSquareDistance(v2 Vector2D, w, h float64) float64 {
+	d := v.TorusDisplacement(v2, w, h)
+	return d.MagnitudeSquared()
+}
+
+// TorusDistance returns the shortest distance between v and v2 on a torus of w x h.
+func (v Vector2D) TorusDistance(v2 Vector2D, w, h float64) float64 {
+	return math.Sqrt(v.TorusSquareDistance(v2, w, h))
+}
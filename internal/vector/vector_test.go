@@ -0,0 +1,48 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	width  = 640
+	height = 480
+)
+
+func TestTorus(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		v, v2        Vector2D
+		displacement Vector2D
+	}{
+		{"same point", Vector2D{10, 20}, Vector2D{10, 20}, Vector2D{0, 0}},
+		{"inside", Vector2D{100, 100}, Vector2D{130, 60}, Vector2D{30, -40}},
+		{"across the left edge", Vector2D{5, 100}, Vector2D{635, 100}, Vector2D{-10, 0}},
+		{"across the right edge", Vector2D{635, 100}, Vector2D{5, 100}, Vector2D{10, 0}},
+		{"across the top edge", Vector2D{100, 5}, Vector2D{100, 475}, Vector2D{0, -10}},
+		{"across the bottom edge", Vector2D{100, 475}, Vector2D{100, 5}, Vector2D{0, 10}},
+		{"across a corner", Vector2D{636, 477}, Vector2D{3, 4}, Vector2D{7, 7}},
+		{"half the width", Vector2D{0, 100}, Vector2D{320, 100}, Vector2D{320, 0}},
+		{"half the width backwards", Vector2D{320, 100}, Vector2D{0, 100}, Vector2D{-320, 0}},
+		{"half the height", Vector2D{100, 0}, Vector2D{100, 240}, Vector2D{0, 240}},
+		{"half the height backwards", Vector2D{100, 240}, Vector2D{100, 0}, Vector2D{0, -240}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.v.TorusDisplacement(tc.v2, width, height)
+			if !d.IsEqual(tc.displacement) {
+				t.Errorf("displacement %v, expected %v", d, tc.displacement)
+			}
+			square := tc.displacement.MagnitudeSquared()
+			if s := tc.v.TorusSquareDistance(tc.v2, width, height); s != square {
+				t.Errorf("square distance %v, expected %v", s, square)
+			}
+			if s := tc.v.TorusDistance(tc.v2, width, height); s != math.Sqrt(square) {
+				t.Errorf("distance %v, expected %v", s, math.Sqrt(square))
+			}
+			if s, r := tc.v.TorusDistance(tc.v2, width, height), tc.v2.TorusDistance(tc.v, width, height); s != r {
+				t.Errorf("distance %v one way and %v the other", s, r)
+			}
+		})
+	}
+}
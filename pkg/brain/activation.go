@@ -0,0 +1,43 @@
+package brain
+
+import (
+	"fmt"
+	"math"
+)
+
+// Activation is the name of a neuron activation function.
+type Activation string
+
+const (
+	Linear  Activation = "linear"
+	Sigmoid Activation = "sigmoid"
+	Tanh    Activation = "tanh"
+	ReLU    Activation = "relu"
+)
+
+var activations = map[Activation]func(float64) float64{
+	Linear: func(x float64) float64 {
+		return x
+	},
+	Sigmoid: func(x float64) float64 {
+		return 1 / (1 + math.Exp(-x))
+	},
+	Tanh: math.Tanh,
+	ReLU: func(x float64) float64 {
+		return math.Max(0, x)
+	},
+}
+
+// ParseActivation returns the activation matching a name.
+func ParseActivation(name string) (Activation, error) {
+	a := Activation(name)
+	if _, ok := activations[a]; !ok {
+		return "", fmt.Errorf("unknown activation %q", name)
+	}
+	return a, nil
+}
+
+// Apply returns the activation of a neuron given the weighted sum of its inputs.
+func (a Activation) Apply(x float64) float64 {
+	return activations[a](x)
+}
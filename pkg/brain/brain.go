@@ -0,0 +1,135 @@
+package brain
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Topology describes the layers of a feed-forward network, from inputs to outputs.
+type Topology struct {
+	// Layers are the number of neurons of each layer, the first one being the inputs.
+	Layers []int `json:"layers"`
+	// Hidden is the activation of hidden layers.
+	Hidden Activation `json:"hidden"`
+	// Output is the activation of the output layer.
+	Output Activation `json:"output"`
+}
+
+// Enabled returns true if the topology describes a network with inputs and outputs.
+func (t Topology) Enabled() bool {
+	return len(t.Layers) >= 2
+}
+
+// Inputs returns the number of inputs of the network.
+func (t Topology) Inputs() int {
+	return t.Layers[0]
+}
+
+// Outputs returns the number of outputs of the network.
+func (t Topology) Outputs() int {
+	return t.Layers[len(t.Layers)-1]
+}
+
+// Weights returns the number of weights, biases included, of the network.
+func (t Topology) Weights() int {
+	n := 0
+	for i := 1; i < len(t.Layers); i++ {
+		n += (t.Layers[i-1] + 1) * t.Layers[i]
+	}
+	return n
+}
+
+// Validate checks the topology can be used to build a network.
+func (t Topology) Validate() error {
+	if !t.Enabled() {
+		return fmt.Errorf("a network needs at least 2 layers, got %d", len(t.Layers))
+	}
+	for i, n := range t.Layers {
+		if n <= 0 {
+			return fmt.Errorf("layer %d has %d neurons", i, n)
+		}
+	}
+	for _, a := range []Activation{t.Hidden, t.Output} {
+		if _, err := ParseActivation(string(a)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RandomWeights returns weights drawn uniformly in [-1, 1] for a given topology.
+func RandomWeights(t Topology, rnd *rand.Rand) []float64 {
+	weights := make([]float64, t.Weights())
+	for i := range weights {
+		weights[i] = rnd.Float64()*2 - 1
+	}
+	return weights
+}
+
+// Network is a fully connected feed-forward neural network.
+type Network struct {
+	topology Topology
+	weights  []float64
+	hidden   func(float64) float64
+	output   func(float64) float64
+	layers   [][]float64
+}
+
+// New creates a network with a given topology and weights.
+// Weights are ordered layer by layer, neuron by neuron, the bias coming after the inputs weights.
+func New(t Topology, weights []float64) (*Network, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if len(weights) != t.Weights() {
+		return nil, fmt.Errorf("topology %v needs %d weights, got %d", t.Layers, t.Weights(), len(weights))
+	}
+	return &Network{
+		topology: t,
+		weights:  weights,
+		hidden:   activations[t.Hidden],
+		output:   activations[t.Output],
+	}, nil
+}
+
+// Topology returns the network topology.
+func (n *Network) Topology() Topology {
+	return n.topology
+}
+
+// Activate feeds the inputs forward through the network and returns its outputs.
+// It fails if the number of inputs does not match the first layer of the topology.
+func (n *Network) Activate(inputs []float64) ([]float64, error) {
+	if len(inputs) != n.topology.Inputs() {
+		return nil, fmt.Errorf("network expects %d inputs, got %d", n.topology.Inputs(), len(inputs))
+	}
+	values := inputs
+	w := 0
+	n.layers = n.layers[:0]
+	for l := 1; l < len(n.topology.Layers); l++ {
+		activation := n.hidden
+		if l == len(n.topology.Layers)-1 {
+			activation = n.output
+		}
+		next := make([]float64, n.topology.Layers[l])
+		for j := range next {
+			sum := 0.0
+			for _, v := range values {
+				sum += n.weights[w] * v
+				w++
+			}
+			// bias
+			sum += n.weights[w]
+			w++
+			next[j] = activation(sum)
+		}
+		values = next
+		n.layers = append(n.layers, values)
+	}
+	return values, nil
+}
+
+// Activations returns the values of the hidden and output layers computed by the last activation.
+func (n *Network) Activations() [][]float64 {
+	return n.layers
+}
@@ -0,0 +1,83 @@
+package brain
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTopologyWeights(t *testing.T) {
+	for _, tc := range []struct {
+		layers  []int
+		weights int
+	}{
+		{[]int{2, 1}, 3},
+		{[]int{3, 4, 2}, 4*4 + 5*2},
+		{[]int{5, 6, 6, 2}, 6*6 + 7*6 + 7*2},
+	} {
+		topology := Topology{Layers: tc.layers, Hidden: Tanh, Output: Tanh}
+		if n := topology.Weights(); n != tc.weights {
+			t.Errorf("topology %v has %d weights, expected %d", tc.layers, n, tc.weights)
+		}
+		if n := len(RandomWeights(topology, rand.New(rand.NewSource(1)))); n != tc.weights {
+			t.Errorf("topology %v got %d random weights, expected %d", tc.layers, n, tc.weights)
+		}
+	}
+}
+
+func TestNewRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		topology Topology
+		weights  int
+	}{
+		{"single layer", Topology{Layers: []int{2}, Hidden: Tanh, Output: Tanh}, 0},
+		{"empty layer", Topology{Layers: []int{2, 0, 1}, Hidden: Tanh, Output: Tanh}, 3},
+		{"unknown activation", Topology{Layers: []int{2, 1}, Hidden: "step", Output: Tanh}, 3},
+		{"missing weights", Topology{Layers: []int{2, 1}, Hidden: Tanh, Output: Tanh}, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.topology, make([]float64, tc.weights)); err == nil {
+				t.Error("invalid network built")
+			}
+		})
+	}
+}
+
+func TestActivate(t *testing.T) {
+	// 2 inputs, a hidden layer of 2 ReLU neurons and a linear output, biases coming after the inputs weights
+	topology := Topology{Layers: []int{2, 2, 1}, Hidden: ReLU, Output: Linear}
+	weights := []float64{
+		1, 2, 0.5, // hidden 0: x + 2y + 0.5
+		-1, 1, 0, // hidden 1: -x + y
+		2, -3, 1, // output: 2h0 - 3h1 + 1
+	}
+	n, err := New(topology, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		inputs []float64
+		hidden []float64
+		output float64
+	}{
+		{[]float64{1, 1}, []float64{3.5, 0}, 8},
+		{[]float64{0, 1}, []float64{2.5, 1}, 3},
+		{[]float64{-2, 0}, []float64{0, 2}, -5},
+	} {
+		outputs, err := n.Activate(tc.inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(outputs) != 1 || math.Abs(outputs[0]-tc.output) > 1e-9 {
+			t.Errorf("inputs %v give %v, expected [%v]", tc.inputs, outputs, tc.output)
+		}
+		layers := n.Activations()
+		if len(layers) != 2 || layers[0][0] != tc.hidden[0] || layers[0][1] != tc.hidden[1] {
+			t.Errorf("inputs %v give hidden activations %v, expected %v", tc.inputs, layers, tc.hidden)
+		}
+	}
+	if _, err := n.Activate([]float64{1, 2, 3}); err == nil {
+		t.Error("activation with too many inputs accepted")
+	}
+}
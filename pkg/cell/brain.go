@@ -0,0 +1,157 @@
+package cell
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/brain"
+	"github.com/jtbonhomme/golife/pkg/neat"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MotorCount is the number of outputs expected from a cell controller.
+	MotorCount int = 2
+)
+
+// Controller turns sensor readings into motor outputs.
+type Controller interface {
+	Activate(inputs []float64) ([]float64, error)
+}
+
+// layered is implemented by controllers exposing the values of their hidden layers after an activation.
+type layered interface {
+	Activations() [][]float64
+}
+
+// SensorCount returns the number of sensor readings fed to a cell controller.
+func (c *Config) SensorCount() int {
+	return 1 + c.VisionRays*rayInputs
+}
+
+// BrainEnabled returns true if cells without any parent are driven by a neural network.
+func (c *Config) BrainEnabled() bool {
+	return len(c.Brain.Hidden) > 0
+}
+
+// NEATEnabled returns true if cells without any parent are driven by a NEAT network.
+func (c *Config) NEATEnabled() bool {
+	return c.Brain.NEAT != nil
+}
+
+// CheckGenome checks the network of a genome, if any, can be fed the sensors of cells built from the config.
+func (c *Config) CheckGenome(g Genome) error {
+	inputs := 0
+	switch {
+	case g.Network != nil:
+		for _, n := range g.Network.Nodes {
+			if n.Kind == neat.Input {
+				inputs++
+			}
+		}
+	case g.Brain.Enabled():
+		inputs = g.Brain.Inputs()
+	default:
+		return nil
+	}
+	if inputs != c.SensorCount() {
+		return fmt.Errorf("genome network has %d inputs, cells have %d sensors", inputs, c.SensorCount())
+	}
+	return nil
+}
+
+// SameBrain returns true if cells built from both configs have the same sensors and the same kind of brain,
+// so that the networks of cells built from one config can be fed the sensors of the other.
+func (c *Config) SameBrain(other *Config) bool {
+	if c.SensorCount() != other.SensorCount() || c.NEATEnabled() != other.NEATEnabled() {
+		return false
+	}
+	if c.Brain.Activation != other.Brain.Activation || len(c.Brain.Hidden) != len(other.Brain.Hidden) {
+		return false
+	}
+	for i, n := range c.Brain.Hidden {
+		if other.Brain.Hidden[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// NewInnovations returns an innovations registry for the NEAT networks of cells.
+func (c *Config) NewInnovations() *neat.Innovations {
+	return neat.NewInnovations(c.SensorCount(), MotorCount)
+}
+
+// BrainTopology returns the topology mapping cell sensors to cell motors through the configured hidden layers.
+func (c *Config) BrainTopology() brain.Topology {
+	layers := append([]int{c.SensorCount()}, c.Brain.Hidden...)
+	layers = append(layers, MotorCount)
+	return brain.Topology{
+		Layers: layers,
+		Hidden: c.Brain.Activation,
+		Output: brain.Tanh,
+	}
+}
+
+// newController builds the controller encoded by a genome, if any.
+func newController(genome Genome) Controller {
+	if genome.Network != nil {
+		network, err := neat.NewNetwork(genome.Network)
+		if err != nil {
+			log.Errorf("invalid neat genome: %s", err.Error())
+			return nil
+		}
+		return network
+	}
+	if !genome.Brain.Enabled() {
+		return nil
+	}
+	network, err := brain.New(genome.Brain, genome.Weights)
+	if err != nil {
+		log.Errorf("invalid brain genome: %s", err.Error())
+		return nil
+	}
+	return network
+}
+
+// sense returns cell sensor readings: its energy, relative to the maximum energy, followed by its vision.
+func (c *Cell) sense() []float64 {
+	return append([]float64{c.energy / c.config.MaxEnergy}, c.VisionInputs()...)
+}
+
+// think returns the acceleration decided by the cell controller from its sensor readings.
+// Outputs are the forward and lateral components of the acceleration, relative to the cell orientation.
+// It fails if the controller does not match the cell sensors and motors.
+func (c *Cell) think() (vector.Vector2D, error) {
+	c.sensors = c.sense()
+	outputs, err := c.controller.Activate(c.sensors)
+	if err != nil {
+		return vector.Vector2D{}, err
+	}
+	if len(outputs) != MotorCount {
+		return vector.Vector2D{}, fmt.Errorf("controller returned %d outputs, expected %d", len(outputs), MotorCount)
+	}
+	c.layers = [][]float64{outputs}
+	if l, ok := c.controller.(layered); ok {
+		c.layers = l.Activations()
+	}
+	forward, lateral := outputs[0], outputs[1]
+	cos, sin := math.Cos(c.orientation), math.Sin(c.orientation)
+	return vector.Vector2D{
+		X: forward*cos - lateral*sin,
+		Y: forward*sin + lateral*cos,
+	}, nil
+}
+
+// Sensors returns the sensor readings fed to the cell controller at the last update,
+// nil if the cell has no controller.
+func (c *Cell) Sensors() []float64 {
+	return c.sensors
+}
+
+// Activations returns the values of the controller layers at the last update, the motor outputs coming last,
+// nil if the cell has no controller.
+func (c *Cell) Activations() [][]float64 {
+	return c.layers
+}
@@ -0,0 +1,56 @@
+package cell
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+func TestCheckGenome(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	config := testConfig(false)
+	moreRays := testConfig(false)
+	moreRays.VisionRays++
+	neatConfig := testConfig(true)
+	neatMoreRays := testConfig(true)
+	neatMoreRays.VisionRays++
+	noBrain := DefaultConfig()
+	noBrain.Brain.Hidden = nil
+
+	for _, tc := range []struct {
+		name   string
+		genome Genome
+		valid  bool
+	}{
+		{"same brain", RandomGenome(rnd, config), true},
+		{"other hidden layers", RandomGenome(rnd, &Config{VisionRays: config.VisionRays, Brain: BrainConfig{Hidden: []int{2, 2}}}), true},
+		{"no brain", RandomGenome(rnd, &noBrain), true},
+		{"more rays", RandomGenome(rnd, moreRays), false},
+		{"same neat inputs", RandomGenome(rnd, neatConfig), true},
+		{"more neat inputs", RandomGenome(rnd, neatMoreRays), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := config.CheckGenome(tc.genome); (err == nil) != tc.valid {
+				t.Errorf("checking genome gives %v, expected valid: %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestSenseEnergy(t *testing.T) {
+	config := testConfig(false)
+	config.MaxEnergy = 200
+	rnd := rand.New(rand.NewSource(6))
+	for _, tc := range []struct {
+		energy, sensed float64
+	}{
+		{200, 1},
+		{50, 0.25},
+	} {
+		c := newCell(rnd, config, vector.Vector2D{X: 10, Y: 10}, 10, tc.energy, RandomGenome(rnd, config), 640, 480, nil)
+		if sensed := c.sense()[0]; sensed != tc.sensed {
+			t.Errorf("energy %v sensed as %v, expected %v", tc.energy, sensed, tc.sensed)
+		}
+	}
+}
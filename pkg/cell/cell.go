@@ -1,32 +1,24 @@
 package cell
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
-	"image/color"
 	"math"
 	"math/rand"
 
 	"github.com/google/uuid"
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	evector "github.com/hajimehoshi/ebiten/v2/vector"
-	"github.com/jtbonhomme/golife/internal/fonts"
 	"github.com/jtbonhomme/golife/internal/vector"
-	colorful "github.com/lucasb-eyer/go-colorful"
-	log "github.com/sirupsen/logrus"
-)
-
-const (
-	cellMaxForce           float64 = 0.3
-	cellMaxVelocity        float64 = 0.9
-	defaultDetectionRadius float64 = 175.0
+	"github.com/jtbonhomme/golife/pkg/food"
+	"github.com/jtbonhomme/golife/pkg/neat"
 )
 
 type Cell struct {
 	size        float64
 	energy      float64
-	rnd10       int32
+	genome      Genome
+	config      *Config
+	rnd         *rand.Rand
 	id          uuid.UUID
 	orientation float64 // theta (radian)
 
@@ -35,197 +27,112 @@ type Cell struct {
 	maxVelocity  float64
 	acceleration vector.Vector2D
 
-	screenWidth  float64
-	screenHeight float64
+	worldWidth  float64
+	worldHeight float64
+
+	isDead     bool
+	deathCause DeathCause
+	death      int
+	predator   uuid.UUID
 
-	debug  bool
-	isDead bool
+	// birth is the tick the cell was born at, kills the number of cells it ate, gathered the energy
+	// it gained by eating, parent the ID of the cell it was born from, nil without any parent,
+	// and lineage the ID of its ancestor without any parent.
+	birth    int
+	kills    int
+	gathered float64
+	parent   uuid.UUID
+	lineage  uuid.UUID
 
 	lastEnergyBurn int
 	lastGrowth     int
 
-	detect          func(vector.Vector2D, float64) []*Cell
-	neighbors       []*Cell
-	detectionRadius float64
-}
-
-func maxVelocity(size float64) float64 {
-	vel := 15 / size
-	if vel > cellMaxVelocity {
-		vel = cellMaxVelocity
-	}
-	return vel
-}
-
-func New(position vector.Vector2D, w, h int, detect func(vector.Vector2D, float64) []*Cell) *Cell {
-	size := 5.0 + rand.Float64()*25.0
-	c := &Cell{
-		position:        position,
-		orientation:     rand.Float64() * 2 * math.Pi,
-		size:            size,
-		energy:          50.0,
-		rnd10:           rand.Int31n(10),
-		id:              uuid.New(),
-		screenWidth:     float64(w),
-		screenHeight:    float64(h),
-		maxVelocity:     maxVelocity(size),
-		lastEnergyBurn:  0,
-		lastGrowth:      int(rand.Int31n(1000)),
-		detect:          detect,
-		neighbors:       []*Cell{},
-		detectionRadius: defaultDetectionRadius,
-	}
-	return c
-}
-
-func (c *Cell) Debug(state bool) {
-	c.debug = state
+	env        Environment
+	neighbors  []*Cell
+	pellets    []*food.Pellet
+	vision     []Ray
+	controller Controller
+	sensors    []float64
+	layers     [][]float64
+	meals      []Meal
 }
 
-func (c *Cell) IsDead() bool {
-	return c.isDead
+// Meal is something eaten by a cell during an update.
+type Meal struct {
+	// Prey is the ID of the eaten cell, empty for a pellet.
+	Prey string
+	// Energy is the energy gained by eating.
+	Energy float64
 }
 
-func (c *Cell) Kill() {
-	c.isDead = true
-	c.energy = 0
+// Environment is what a cell perceives of the world around it.
+type Environment interface {
+	// Detect returns the living cells located in a radius from a position.
+	Detect(pos vector.Vector2D, radius float64) []*Cell
+	// DetectFood returns the pellets not eaten yet located in a radius from a position.
+	DetectFood(pos vector.Vector2D, radius float64) []*food.Pellet
+	// Innovations returns the registry of the structural mutations of NEAT networks.
+	Innovations() *neat.Innovations
 }
 
-func maxCounter(index, rnd10 int) int {
-	return 50 + rnd10 + (25*index+rnd10)%64
+// newID draws a random UUID from rnd. Whole values are drawn from rnd, rather than reading bytes
+// from it, so that the random source state only depends on the number of values drawn.
+func newID(rnd *rand.Rand) uuid.UUID {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint64(b[:8], rnd.Uint64())
+	binary.LittleEndian.PutUint64(b[8:], rnd.Uint64())
+	return uuid.Must(uuid.NewRandomFromReader(bytes.NewReader(b)))
 }
 
-func angularToCartesian(dist, orientation float64) (x, y float32) {
-	return float32(dist * math.Cos(orientation)), float32(dist * math.Sin(orientation))
+// New creates a cell without any parent at a given position and tick, drawing its random properties from rnd.
+// The config is shared by all cells of a world.
+func New(rnd *rand.Rand, config *Config, position vector.Vector2D, birth, w, h int, env Environment) *Cell {
+	return NewWithGenome(rnd, config, position, RandomGenome(rnd, config), birth, w, h, env)
 }
 
-func addVector(position vector.Vector2D, dist, orientation float64) (x, y float32) {
-	acX, acY := angularToCartesian(dist, orientation)
-	return float32(position.X) + acX, float32(position.Y) + acY
+// NewWithGenome creates a cell without any parent carrying a given genome, drawing its other random
+// properties from rnd.
+func NewWithGenome(rnd *rand.Rand, config *Config, position vector.Vector2D, genome Genome, birth, w, h int, env Environment) *Cell {
+	size := config.MinSize + rnd.Float64()*(config.MaxSize-config.MinSize)
+	c := newCell(rnd, config, position, size, config.InitialEnergy, genome, float64(w), float64(h), env)
+	c.birth = birth
+	c.lineage = c.id
+	c.orientation = rnd.Float64() * 2 * math.Pi
+	c.lastGrowth = int(rnd.Int31n(int32(c.genome.GrowthInterval)))
+	return c
 }
 
-func (c *Cell) drawCellBody(screen *ebiten.Image, counter int, emptySubImage *ebiten.Image) {
-	var path evector.Path
-	npoints := 16
-
-	indexToDirection := func(i int) float64 {
-		return c.orientation - float64(2*i+1)*math.Pi/float64(npoints)
-	}
-	indexToDist := func(i, counter int) float64 {
-		return c.size + c.size*0.1*math.Sin(float64(counter)*2*math.Pi/float64(maxCounter(i, int(c.rnd10))))
-	}
-
-	for i := 0; i <= npoints; i++ {
-		if i == 0 {
-			path.MoveTo(addVector(c.position, indexToDist(i, counter), indexToDirection(i)))
-			continue
-		}
-		cpx0, cpy0 := addVector(c.position, indexToDist(i, counter), indexToDirection(i-1)-math.Pi/16)
-		cpx1, cpy1 := addVector(c.position, indexToDist(i, counter), indexToDirection(i)+math.Pi/16)
-		cpx2, cpy2 := addVector(c.position, indexToDist(i, counter), indexToDirection(i))
-		path.CubicTo(cpx0, cpy0, cpx1, cpy1, cpx2, cpy2)
-	}
-
-	// Get the color (120° is green, 0° is red)
-	cellColor := colorful.HSLuv(c.size*360/50, 1, 0.5)
-
-	op := &ebiten.DrawTrianglesOptions{
-		FillRule: ebiten.EvenOdd,
-	}
-	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
-	for i := range vs {
-		vs[i].SrcX = 1
-		vs[i].SrcY = 1
-		vs[i].ColorR = float32(cellColor.R)
-		vs[i].ColorG = float32(cellColor.G)
-		vs[i].ColorB = float32(cellColor.B)
+func newCell(rnd *rand.Rand, config *Config, position vector.Vector2D, size, energy float64, genome Genome, w, h float64, env Environment) *Cell {
+	c := &Cell{
+		position:       position,
+		size:           size,
+		energy:         energy,
+		genome:         genome,
+		config:         config,
+		rnd:            rnd,
+		id:             newID(rnd),
+		worldWidth:     w,
+		worldHeight:    h,
+		maxVelocity:    config.maxVelocity(genome.VelocityFactor, size),
+		lastEnergyBurn: 0,
+		lastGrowth:     0,
+		env:            env,
+		neighbors:      []*Cell{},
+		controller:     newController(genome),
 	}
-	screen.DrawTriangles(vs, is, emptySubImage, op)
+	return c
 }
 
-func (c *Cell) drawEyes(screen *ebiten.Image, dist, side, size, bg float64, emptySubImage *ebiten.Image) {
-	var path evector.Path
-
-	randomizedFloat64 := func(in float64) float64 {
-		return in + rand.Float64()*2
-	}
-
-	cpx0, cpy0 := addVector(c.position, dist-randomizedFloat64(size), c.orientation+side*math.Pi/randomizedFloat64(12))
-
-	path.Arc(cpx0, cpy0, float32(size), float32(0), float32(2*math.Pi), evector.Clockwise)
-
-	op := &ebiten.DrawTrianglesOptions{
-		FillRule: ebiten.EvenOdd,
-	}
-	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
-	for i := range vs {
-		vs[i].SrcX = 1
-		vs[i].SrcY = 1
-		vs[i].ColorR = float32(bg) / float32(0xff)
-		vs[i].ColorG = float32(bg) / float32(0xff)
-		vs[i].ColorB = float32(bg) / float32(0xff)
-	}
-	screen.DrawTriangles(vs, is, emptySubImage, op)
-}
-
-func (c *Cell) Draw(screen *ebiten.Image, counter int, emptySubImage *ebiten.Image) {
-	c.drawCellBody(screen, counter, emptySubImage)
-	c.drawEyes(screen, c.size*0.9, -1, c.size*0.1, 0xff, emptySubImage)
-	c.drawEyes(screen, c.size*0.9, -1, c.size*0.05, 0x00, emptySubImage)
-	c.drawEyes(screen, c.size*0.9, 1, c.size*0.1, 0xff, emptySubImage)
-	c.drawEyes(screen, c.size*0.9, 1, c.size*0.05, 0x00, emptySubImage)
-	if c.debug {
-		c.DrawBodyBoundaryBox(screen)
-		msg := c.String()
-		textDim := text.BoundString(fonts.MonoSansRegularFont, msg)
-		textWidth := textDim.Max.X - textDim.Min.X
-		text.Draw(screen,
-			msg,
-			fonts.MonoSansRegularFont,
-			int(c.position.X)-textWidth/2,
-			int(c.position.Y+c.size+5),
-			color.Gray16{0x999f})
-	}
+func (c *Cell) IsDead() bool {
+	return c.isDead
 }
 
-// DrawBodyBoundaryBox draws a box around the body, based on its dimension.
-func (c *Cell) DrawBodyBoundaryBox(screen *ebiten.Image) {
-	// Top boundary
-	ebitenutil.DrawLine(
-		screen,
-		c.position.X-c.size,
-		c.position.Y-c.size,
-		c.position.X+c.size,
-		c.position.Y-c.size,
-		color.Gray16{0xbbbb},
-	)
-	// Right boundary
-	ebitenutil.DrawLine(
-		screen,
-		c.position.X+c.size,
-		c.position.Y-c.size,
-		c.position.X+c.size,
-		c.position.Y+c.size,
-		color.Gray16{0xbbbb},
-	)
-	// Bottom boundary
-	ebitenutil.DrawLine(
-		screen,
-		c.position.X-c.size,
-		c.position.Y+c.size,
-		c.position.X+c.size,
-		c.position.Y+c.size,
-		color.Gray16{0xbbbb},
-	)
-	// Left boundary
-	ebitenutil.DrawLine(
-		screen,
-		c.position.X-c.size,
-		c.position.Y-c.size,
-		c.position.X-c.size,
-		c.position.Y+c.size,
-		color.Gray16{0xbbbb},
-	)
+// Kill makes the cell die at a given tick for a given cause.
+func (c *Cell) Kill(counter int, cause DeathCause) {
+	c.isDead = true
+	c.deathCause = cause
+	c.death = counter
+	c.energy = 0
 }
 
 // String displays cell information as a string.
@@ -243,13 +150,43 @@ func (c *Cell) String() string {
 		c.acceleration.Y)
 }
 
-// Eat absorb another cell.
-func (c *Cell) Eat(c2 *Cell) {
-	c.energy += c2.Energy() * 0.5
-	if c.energy > 100.0 {
-		c.energy = 100.0
-	}
-	c2.Kill()
+// Eat absorb another cell at a given tick.
+func (c *Cell) Eat(c2 *Cell, counter int) {
+	energy := c.gain(c2.Energy() * c.config.Diets.Of(c.Diet()).CellEfficiency)
+	c2.Kill(counter, Predation)
+	c2.predator = c.id
+	c.kills++
+	c.meals = append(c.meals, Meal{Prey: c2.ID(), Energy: energy})
+}
+
+// EatFood absorbs a pellet.
+func (c *Cell) EatFood(p *food.Pellet) {
+	energy := c.gain(p.Consume() * c.config.Diets.Of(c.Diet()).FoodEfficiency)
+	c.meals = append(c.meals, Meal{Energy: energy})
+}
+
+// gain adds energy gained by eating, up to the maximum energy a cell can store, and returns the energy added.
+func (c *Cell) gain(energy float64) float64 {
+	energy = math.Min(energy, c.config.MaxEnergy-c.energy)
+	c.energy += energy
+	c.gathered += energy
+	return energy
+}
+
+// Meals returns what the cell ate during its last update.
+func (c *Cell) Meals() []Meal {
+	return c.meals
+}
+
+// Touches returns true if the physical body is in contact with a pellet.
+func (c *Cell) Touches(p *food.Pellet) bool {
+	radii := c.size + p.Size
+	return c.position.TorusSquareDistance(p.Position, c.worldWidth, c.worldHeight) < radii*radii
+}
+
+// CanEat returns true if the cell diet includes other cells and it is big enough to eat another one.
+func (c *Cell) CanEat(c2 *Cell) bool {
+	return c.Diet().EatsCells() && c.size > c2.size*c.genome.EatRatio
 }
 
 // Size return cell size.
@@ -267,34 +204,20 @@ func (c *Cell) ID() string {
 	return c.id.String()
 }
 
+// ShortID returns the first characters of a cell ID, enough to tell cells apart.
+func ShortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 // Intersect returns true if the physical body collide another one.
-// Collision is computed based on Axis-Aligned Bounding Boxes.
+// Collision is computed between circles of radius size centered on the cells positions.
 // https://developer.mozilla.org/en-US/docs/Games/Techniques/2D_collision_detection
 func (c *Cell) Intersect(c2 *Cell) bool {
-	ax, ay := c.position.X, c.position.Y
-	aw, ah := c.size, c.size
-
-	bx, by := c2.position.X, c2.position.Y
-	bw, bh := c2.size, c2.size
-
-	return (ax < bx+bw && ay < by+bh) && (ax+aw > bx && ay+ah > by)
-}
-
-// IntersectMultiple checks if multiple physical bodies are colliding with the first
-func (c *Cell) IntersectMultiple(cells map[string]*Cell) (string, bool) {
-	for _, c2 := range cells {
-		if c.Intersect(c2) {
-			log.Warnf("%s [%d , %d] (%dx%d) intersect with %s [%d , %d] (%dx%d)",
-				c.ID(),
-				int(c.position.X), int(c.position.Y),
-				int(c.size), int(c.size),
-				c2.ID(),
-				int(c2.position.X), int(c2.position.Y),
-				int(c2.size), int(c2.size))
-			return c2.ID(), true
-		}
-	}
-	return "", false
+	radii := c.size + c2.size
+	return c.position.TorusSquareDistance(c2.position, c.worldWidth, c.worldHeight) < radii*radii
 }
 
 // Position returns cell position.
@@ -302,12 +225,76 @@ func (c *Cell) Position() vector.Vector2D {
 	return c.position
 }
 
+// displacement returns the shortest vector from the cell to a position, the world being a torus.
+func (c *Cell) displacement(pos vector.Vector2D) vector.Vector2D {
+	return c.position.TorusDisplacement(pos, c.worldWidth, c.worldHeight)
+}
+
+// distance returns the shortest distance from the cell to a position, the world being a torus.
+func (c *Cell) distance(pos vector.Vector2D) float64 {
+	return c.position.TorusDistance(pos, c.worldWidth, c.worldHeight)
+}
+
 // Velocity returns cell velocity.
 func (c *Cell) Velocity() vector.Vector2D {
 	return c.velocity
 }
 
+// ResetMaxVelocity recomputes the cell maximum velocity from its velocity gene and its current size,
+// for a change of the cells maximum velocity to apply to it.
+func (c *Cell) ResetMaxVelocity() {
+	c.maxVelocity = c.config.maxVelocity(c.genome.VelocityFactor, c.size)
+}
+
 // DetectionRadius returns cell detection radius.
 func (c *Cell) DetectionRadius() float64 {
-	return c.detectionRadius
+	return c.genome.DetectionRadius
+}
+
+// Orientation returns cell orientation (radian).
+func (c *Cell) Orientation() float64 {
+	return c.orientation
+}
+
+// Rnd10 returns the random factor used to animate the cell body.
+func (c *Cell) Rnd10() int32 {
+	return c.genome.Rnd10
+}
+
+// Birth returns the tick the cell was born at.
+func (c *Cell) Birth() int {
+	return c.birth
+}
+
+// Age returns the number of ticks elapsed since the cell birth.
+func (c *Cell) Age(counter int) int {
+	return counter - c.birth
+}
+
+// Parent returns the ID of the cell this one was born from, or an empty string if it has no parent.
+func (c *Cell) Parent() string {
+	if c.parent == uuid.Nil {
+		return ""
+	}
+	return c.parent.String()
+}
+
+// Kills returns the number of cells eaten by the cell.
+func (c *Cell) Kills() int {
+	return c.kills
+}
+
+// Gathered returns the energy gained by the cell by eating.
+func (c *Cell) Gathered() float64 {
+	return c.gathered
+}
+
+// Lineage returns the ID of the cell ancestor without any parent, the cell itself if it has no parent.
+func (c *Cell) Lineage() string {
+	return c.lineage.String()
+}
+
+// Genome returns cell genome.
+func (c *Cell) Genome() Genome {
+	return c.genome
 }
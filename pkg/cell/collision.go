@@ -0,0 +1,52 @@
+package cell
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+// Collide resolves the collision between two intersecting cells: they are pushed apart
+// until they no longer overlap, and bounce off each other as in an elastic collision.
+// The mass of a cell is proportional to its area.
+func (c *Cell) Collide(c2 *Cell) {
+	normal := c.displacement(c2.position)
+	dist := math.Sqrt(normal.MagnitudeSquared())
+	if dist == 0 {
+		// cells are exactly at the same position, pick any direction
+		normal = vector.Vector2D{X: 1, Y: 0}
+	} else {
+		normal.Divide(dist)
+	}
+
+	m1, m2 := c.size*c.size, c2.size*c2.size
+	total := m1 + m2
+
+	// push apart, the lighter cell moving the most
+	overlap := c.size + c2.size - dist
+	if overlap > 0 {
+		push := normal
+		push.Multiply(-overlap * m2 / total)
+		c.position.Add(push)
+		push = normal
+		push.Multiply(overlap * m1 / total)
+		c2.position.Add(push)
+		c.wrapPosition()
+		c2.wrapPosition()
+	}
+
+	// exchange momentum along the normal if cells are moving towards each other
+	relative := c.velocity
+	relative.Subtract(c2.velocity)
+	approach := relative.X*normal.X + relative.Y*normal.Y
+	if approach <= 0 {
+		return
+	}
+	impulse := 2 * approach / total
+	v1 := normal
+	v1.Multiply(-impulse * m2)
+	c.velocity.Add(v1)
+	v2 := normal
+	v2.Multiply(impulse * m1)
+	c2.velocity.Add(v2)
+}
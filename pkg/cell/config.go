@@ -0,0 +1,161 @@
+package cell
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// BrainConfig describes the neural network of cells without any parent.
+type BrainConfig struct {
+	// Hidden are the sizes of the hidden layers. Cells are driven by hard-coded
+	// flee and chase rules when there is none.
+	Hidden []int `json:"hidden"`
+	// Activation is the activation of hidden layers.
+	Activation brain.Activation `json:"activation"`
+	// NEAT makes cells driven by networks evolving their topology, starting from inputs connected to outputs,
+	// in place of hidden layers.
+	NEAT *neat.Config `json:"neat,omitempty"`
+}
+
+// Config holds the parameters of cells life.
+type Config struct {
+	// MinSize and MaxSize bound the size of cells without any parent.
+	MinSize float64 `json:"minSize"`
+	MaxSize float64 `json:"maxSize"`
+	// InitialEnergy is the energy of cells without any parent.
+	InitialEnergy float64 `json:"initialEnergy"`
+	// MaxEnergy is the maximum energy a cell can store.
+	MaxEnergy float64 `json:"maxEnergy"`
+	// MaxAge is the number of ticks after which a cell dies of old age, never if 0.
+	MaxAge int `json:"maxAge"`
+	// Diets gives what cells eat and how efficiently, depending on their diet.
+	Diets DietsConfig `json:"diets"`
+	// MaxForce limits the acceleration of a fleeing cell.
+	MaxForce float64 `json:"maxForce"`
+	// MaxVelocity limits the velocity of all cells.
+	MaxVelocity float64 `json:"maxVelocity"`
+	// NeighborRadius is the distance up to which a cell detects its neighbors.
+	NeighborRadius float64 `json:"neighborRadius"`
+	// EnergyBurn is the energy burnt every EnergyBurnInterval ticks.
+	EnergyBurn         float64 `json:"energyBurn"`
+	EnergyBurnInterval int     `json:"energyBurnInterval"`
+	// GrowthStep is the size gained for a cost of GrowthCost energy at each growth step.
+	GrowthStep float64 `json:"growthStep"`
+	GrowthCost float64 `json:"growthCost"`
+	// DivisionSize and DivisionEnergy are the size and energy a cell must reach before dividing.
+	DivisionSize   float64 `json:"divisionSize"`
+	DivisionEnergy float64 `json:"divisionEnergy"`
+	// MutationRate is the probability for each gene to mutate when a genome is copied.
+	MutationRate float64 `json:"mutationRate"`
+	// MutationScale is the standard deviation of a mutation, relative to the gene value.
+	// It is absolute for brain weights.
+	MutationScale float64 `json:"mutationScale"`
+	// VisionRays is the number of rays cast by a cell to see around it.
+	VisionRays int `json:"visionRays"`
+	// VisionFieldOfView is the angle covered by vision rays, centered on the cell orientation (degree).
+	VisionFieldOfView float64 `json:"visionFieldOfView"`
+	// Brain describes the neural network of cells without any parent.
+	Brain BrainConfig `json:"brain"`
+	// Founder holds the traits of cells without any parent.
+	Founder Traits `json:"founder"`
+}
+
+// DefaultConfig returns the default cells parameters.
+func DefaultConfig() Config {
+	return Config{
+		MinSize:            5,
+		MaxSize:            30,
+		InitialEnergy:      50,
+		MaxEnergy:          100,
+		Diets:              DefaultDietsConfig(),
+		MaxForce:           0.3,
+		MaxVelocity:        0.9,
+		NeighborRadius:     250,
+		EnergyBurn:         2,
+		EnergyBurnInterval: 150,
+		GrowthStep:         5,
+		GrowthCost:         5,
+		DivisionSize:       40,
+		DivisionEnergy:     60,
+		MutationRate:       0.1,
+		MutationScale:      0.1,
+		VisionRays:         7,
+		VisionFieldOfView:  120,
+		Brain: BrainConfig{
+			Hidden:     []int{},
+			Activation: brain.Tanh,
+		},
+		Founder: Traits{
+			DetectionRadius: 175,
+			VelocityFactor:  15,
+			GrowthInterval:  1000,
+			EatRatio:        1.1,
+		},
+	}
+}
+
+// Validate checks the parameters are consistent.
+func (c Config) Validate() error {
+	positives := []struct {
+		name  string
+		value float64
+	}{
+		{"minSize", c.MinSize},
+		{"maxEnergy", c.MaxEnergy},
+		{"maxVelocity", c.MaxVelocity},
+		{"energyBurnInterval", float64(c.EnergyBurnInterval)},
+		{"visionRays", float64(c.VisionRays)},
+		{"founder.detectionRadius", c.Founder.DetectionRadius},
+		{"founder.velocityFactor", c.Founder.VelocityFactor},
+		{"founder.growthInterval", float64(c.Founder.GrowthInterval)},
+	}
+	for _, p := range positives {
+		if p.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", p.name, p.value)
+		}
+	}
+	if c.MaxSize < c.MinSize {
+		return fmt.Errorf("maxSize (%v) must be greater than minSize (%v)", c.MaxSize, c.MinSize)
+	}
+	if c.InitialEnergy <= 0 || c.InitialEnergy > c.MaxEnergy {
+		return fmt.Errorf("initialEnergy (%v) must be in ]0, maxEnergy]", c.InitialEnergy)
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("maxAge must not be negative, got %d", c.MaxAge)
+	}
+	if c.MutationRate < 0 || c.MutationRate > 1 {
+		return fmt.Errorf("mutationRate (%v) must be in [0, 1]", c.MutationRate)
+	}
+	if c.Founder.EatRatio < 1 {
+		return fmt.Errorf("founder.eatRatio (%v) must be at least 1", c.Founder.EatRatio)
+	}
+	if err := c.Diets.Validate(); err != nil {
+		return fmt.Errorf("invalid diets: %w", err)
+	}
+	if c.NEATEnabled() {
+		if _, err := brain.ParseActivation(string(c.Brain.Activation)); err != nil {
+			return err
+		}
+		if err := c.Brain.NEAT.Validate(); err != nil {
+			return fmt.Errorf("invalid neat: %w", err)
+		}
+		return nil
+	}
+	if c.BrainEnabled() {
+		return c.BrainTopology().Validate()
+	}
+	return nil
+}
+
+// fieldOfView returns the vision field of view in radian.
+func (c *Config) fieldOfView() float64 {
+	return c.VisionFieldOfView * math.Pi / 180
+}
+
+// maxVelocity returns the maximum velocity of a cell, given its velocity gene and its size.
+func (c *Config) maxVelocity(factor, size float64) float64 {
+	return math.Min(factor/size, c.MaxVelocity)
+}
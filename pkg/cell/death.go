@@ -0,0 +1,40 @@
+package cell
+
+import "github.com/google/uuid"
+
+// DeathCause is the reason why a cell died.
+type DeathCause string
+
+const (
+	// Starvation is the death of a cell running out of energy.
+	Starvation DeathCause = "starvation"
+	// Predation is the death of a cell eaten by another one.
+	Predation DeathCause = "predation"
+	// Division is the disappearance of a cell divided into two children.
+	Division DeathCause = "division"
+	// OldAge is the death of a cell reaching the maximum age.
+	OldAge DeathCause = "old_age"
+	// Despawn is the removal of a cell from the world by an external input.
+	Despawn DeathCause = "despawn"
+)
+
+// DeathCauses lists all the causes of death.
+var DeathCauses = []DeathCause{Starvation, Predation, Division, OldAge, Despawn}
+
+// Death returns the tick the cell died at, 0 if it is alive.
+func (c *Cell) Death() int {
+	return c.death
+}
+
+// DeathCause returns the reason why the cell died, or an empty cause if it is alive.
+func (c *Cell) DeathCause() DeathCause {
+	return c.deathCause
+}
+
+// Predator returns the ID of the cell which ate this one, or an empty string if it was not eaten.
+func (c *Cell) Predator() string {
+	if c.predator == uuid.Nil {
+		return ""
+	}
+	return c.predator.String()
+}
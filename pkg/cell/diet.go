@@ -0,0 +1,120 @@
+package cell
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Diet is the role of a cell in the food chain, deciding what it can eat.
+type Diet string
+
+const (
+	// Herbivore cells only eat food pellets.
+	Herbivore Diet = "herbivore"
+	// Carnivore cells only eat other cells.
+	Carnivore Diet = "carnivore"
+	// Omnivore cells eat both food pellets and other cells.
+	Omnivore Diet = "omnivore"
+)
+
+// Diets lists all the diets.
+var Diets = []Diet{Herbivore, Carnivore, Omnivore}
+
+// EatsCells returns true if cells with this diet eat other cells.
+func (d Diet) EatsCells() bool {
+	return d == Carnivore || d == Omnivore
+}
+
+// EatsFood returns true if cells with this diet eat food pellets.
+func (d Diet) EatsFood() bool {
+	return d == Herbivore || d == Omnivore
+}
+
+// DietConfig holds the parameters of cells with a given diet.
+type DietConfig struct {
+	// Share is the relative proportion of cells without any parent having this diet.
+	Share float64 `json:"share"`
+	// CellEfficiency is the part of a prey energy gained by a cell eating it.
+	CellEfficiency float64 `json:"cellEfficiency"`
+	// FoodEfficiency is the part of a pellet energy gained by a cell eating it.
+	FoodEfficiency float64 `json:"foodEfficiency"`
+}
+
+// DietsConfig holds the parameters of each diet.
+type DietsConfig struct {
+	Herbivore DietConfig `json:"herbivore"`
+	Carnivore DietConfig `json:"carnivore"`
+	Omnivore  DietConfig `json:"omnivore"`
+	// MutationRate is the probability for the diet of an offspring to be drawn again.
+	MutationRate float64 `json:"mutationRate"`
+}
+
+// DefaultDietsConfig returns the default diets parameters.
+func DefaultDietsConfig() DietsConfig {
+	return DietsConfig{
+		Herbivore:    DietConfig{Share: 0.4, FoodEfficiency: 1},
+		Carnivore:    DietConfig{Share: 0.3, CellEfficiency: 0.6},
+		Omnivore:     DietConfig{Share: 0.3, CellEfficiency: 0.4, FoodEfficiency: 0.6},
+		MutationRate: 0.01,
+	}
+}
+
+// Of returns the parameters of a given diet.
+func (c DietsConfig) Of(d Diet) DietConfig {
+	switch d {
+	case Herbivore:
+		return c.Herbivore
+	case Carnivore:
+		return c.Carnivore
+	default:
+		return c.Omnivore
+	}
+}
+
+// Validate checks the parameters are consistent.
+func (c DietsConfig) Validate() error {
+	total := 0.0
+	for _, d := range Diets {
+		dc := c.Of(d)
+		if dc.Share < 0 || dc.CellEfficiency < 0 || dc.FoodEfficiency < 0 {
+			return fmt.Errorf("%s share and efficiencies must not be negative", d)
+		}
+		total += dc.Share
+	}
+	if total <= 0 {
+		return fmt.Errorf("at least one diet must have a positive share")
+	}
+	if c.MutationRate < 0 || c.MutationRate > 1 {
+		return fmt.Errorf("mutationRate (%v) must be in [0, 1]", c.MutationRate)
+	}
+	return nil
+}
+
+// random draws a diet from rnd, following the diets shares.
+func (c DietsConfig) random(rnd *rand.Rand) Diet {
+	total := 0.0
+	for _, d := range Diets {
+		total += c.Of(d).Share
+	}
+	x := rnd.Float64() * total
+	for _, d := range Diets {
+		x -= c.Of(d).Share
+		if x < 0 {
+			return d
+		}
+	}
+	return Diets[len(Diets)-1]
+}
+
+// mutate returns the diet of an offspring, drawn again from rnd with a probability of MutationRate.
+func (c DietsConfig) mutate(rnd *rand.Rand, d Diet) Diet {
+	if rnd.Float64() >= c.MutationRate {
+		return d
+	}
+	return c.random(rnd)
+}
+
+// Diet returns the cell diet.
+func (c *Cell) Diet() Diet {
+	return c.genome.Diet
+}
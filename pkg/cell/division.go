@@ -0,0 +1,42 @@
+package cell
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+// CanDivide returns true if the cell is big enough and has enough energy to divide.
+func (c *Cell) CanDivide() bool {
+	return !c.isDead && c.size >= c.config.DivisionSize && c.energy >= c.config.DivisionEnergy
+}
+
+// Divide splits the cell into two children sharing its size and energy,
+// each one carrying a copy of its genome. The parent cell disappears.
+func (c *Cell) Divide(counter int) []*Cell {
+	children := make([]*Cell, 0, 2)
+	for _, side := range []float64{-1, 1} {
+		// children are placed side by side, perpendicular to the parent orientation
+		offset := vector.Vector2D{
+			X: math.Cos(c.orientation + side*math.Pi/2),
+			Y: math.Sin(c.orientation + side*math.Pi/2),
+		}
+		offset.Multiply(c.size / 2)
+		position := c.position
+		position.Add(offset)
+
+		genome := c.genome.Mutate(c.rnd, c.config, c.env.Innovations())
+		child := newCell(c.rnd, c.config, position, c.size/2, c.energy/2, genome, c.worldWidth, c.worldHeight, c.env)
+		child.birth = counter
+		child.parent = c.id
+		child.lineage = c.lineage
+		child.orientation = c.orientation
+		child.velocity = c.velocity
+		child.lastEnergyBurn = counter
+		child.lastGrowth = counter
+		child.UpdatePosition()
+		children = append(children, child)
+	}
+	c.Kill(counter, Division)
+	return children
+}
@@ -0,0 +1,147 @@
+package cell
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// Traits are the heritable parameters of a cell behavior.
+type Traits struct {
+	// DetectionRadius is the distance up to which a cell chases a prey.
+	DetectionRadius float64 `json:"detectionRadius"`
+	// VelocityFactor gives the cell maximum velocity, divided by its size.
+	VelocityFactor float64 `json:"velocityFactor"`
+	// GrowthInterval is the number of ticks between two growth steps.
+	GrowthInterval int `json:"growthInterval"`
+	// EatRatio is the minimal size ratio for a cell to eat another one.
+	EatRatio float64 `json:"eatRatio"`
+}
+
+// Genome holds the genetic information of a cell, transmitted to its offspring.
+type Genome struct {
+	// Rnd10 animates the cell body.
+	Rnd10 int32 `json:"rnd10"`
+	Traits
+	// Diet is the role of the cell in the food chain.
+	Diet Diet `json:"diet"`
+	// Brain is the topology of the cell neural network, if any.
+	Brain brain.Topology `json:"brain"`
+	// Weights are the weights of the cell neural network.
+	Weights []float64 `json:"weights,omitempty"`
+	// Network encodes the cell NEAT network, if any, in place of Brain and Weights.
+	Network *neat.Genome `json:"network,omitempty"`
+}
+
+// RandomGenome returns the genome of cells without any parent: founder genes from config,
+// with a body animation and brain weights drawn from rnd.
+func RandomGenome(rnd *rand.Rand, config *Config) Genome {
+	g := Genome{
+		Rnd10:  rnd.Int31n(10),
+		Traits: config.Founder,
+		Diet:   config.Diets.random(rnd),
+	}
+	if config.NEATEnabled() {
+		g.Network = neat.Minimal(rnd, config.SensorCount(), MotorCount, config.Brain.Activation, brain.Tanh)
+	} else if config.BrainEnabled() {
+		g.Brain = config.BrainTopology()
+		g.Weights = brain.RandomWeights(g.Brain, rnd)
+	}
+	return g
+}
+
+// Copy returns a copy of the genome to be transmitted to an offspring.
+// Each gene mutates with a probability of rate, following a gaussian distribution
+// of standard deviation scale, relative to the gene value.
+func (g Genome) Copy(rnd *rand.Rand, rate, scale float64) Genome {
+	mutate := func(gene float64) float64 {
+		if rnd.Float64() >= rate {
+			return gene
+		}
+		return gene + rnd.NormFloat64()*scale*gene
+	}
+
+	g.DetectionRadius = math.Max(mutate(g.DetectionRadius), 1)
+	g.VelocityFactor = math.Max(mutate(g.VelocityFactor), 0)
+	g.GrowthInterval = int(math.Max(math.Round(mutate(float64(g.GrowthInterval))), 1))
+	// a cell can not eat a bigger one
+	g.EatRatio = math.Max(mutate(g.EatRatio), 1)
+
+	if g.Weights != nil {
+		weights := make([]float64, len(g.Weights))
+		for i, w := range g.Weights {
+			weights[i] = w
+			if rnd.Float64() < rate {
+				weights[i] += rnd.NormFloat64() * scale
+			}
+		}
+		g.Weights = weights
+	}
+	if g.Network != nil {
+		g.Network = g.Network.Copy()
+	}
+	return g
+}
+
+// Mutate returns a copy of the genome to be transmitted to an offspring,
+// mutated following the mutation parameters of config. The NEAT network, if any,
+// registers its structural mutations in innovations.
+func (g Genome) Mutate(rnd *rand.Rand, config *Config, innovations *neat.Innovations) Genome {
+	g = g.Copy(rnd, config.MutationRate, config.MutationScale)
+	g.Diet = config.Diets.mutate(rnd, g.Diet)
+	if g.Network != nil {
+		// networks of genomes restored without any NEAT config only see their weights mutate
+		structure := neat.Config{}
+		if config.NEATEnabled() {
+			structure = *config.Brain.NEAT
+		}
+		g.Network = g.Network.Mutate(rnd, config.MutationRate, config.MutationScale, structure, innovations)
+	}
+	return g
+}
+
+// Crossover returns a genome mixing two parent genomes, each gene coming from one of them
+// with an equal probability. Brain weights are only mixed if both parents share the same topology,
+// and come from the first parent otherwise. NEAT networks are mixed by innovation, a being the fitter parent,
+// and copied from a if b has none.
+func Crossover(rnd *rand.Rand, a, b Genome) Genome {
+	pick := func() bool {
+		return rnd.Float64() < 0.5
+	}
+	child := a
+	if pick() {
+		child.DetectionRadius = b.DetectionRadius
+	}
+	if pick() {
+		child.VelocityFactor = b.VelocityFactor
+	}
+	if pick() {
+		child.GrowthInterval = b.GrowthInterval
+	}
+	if pick() {
+		child.EatRatio = b.EatRatio
+	}
+	if pick() {
+		child.Diet = b.Diet
+	}
+	if a.Weights != nil {
+		child.Weights = make([]float64, len(a.Weights))
+		copy(child.Weights, a.Weights)
+		if reflect.DeepEqual(a.Brain, b.Brain) {
+			for i := range child.Weights {
+				if pick() {
+					child.Weights[i] = b.Weights[i]
+				}
+			}
+		}
+	}
+	if a.Network != nil && b.Network != nil {
+		child.Network = neat.Crossover(rnd, a.Network, b.Network)
+	} else if a.Network != nil {
+		child.Network = a.Network.Copy()
+	}
+	return child
+}
@@ -0,0 +1,111 @@
+package cell
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// testConfig returns the default cell parameters with a brain of a given kind.
+func testConfig(withNEAT bool) *Config {
+	config := DefaultConfig()
+	config.Brain.Hidden = []int{4}
+	if withNEAT {
+		neatConfig := neat.DefaultConfig()
+		config.Brain.NEAT = &neatConfig
+	}
+	return &config
+}
+
+func TestCopyWithoutMutation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := RandomGenome(rnd, testConfig(false))
+	c := g.Copy(rnd, 0, 0.5)
+	if !reflect.DeepEqual(g, c) {
+		t.Fatalf("genome copied without mutation differs: %+v, expected %+v", c, g)
+	}
+	c.Weights[0]++
+	if g.Weights[0] == c.Weights[0] {
+		t.Error("copy shares its weights with the original genome")
+	}
+}
+
+func TestCopyMutates(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := RandomGenome(rnd, testConfig(false))
+	g.EatRatio = 1
+	for i := 0; i < 100; i++ {
+		c := g.Copy(rnd, 1, 0.5)
+		if c.DetectionRadius == g.DetectionRadius || c.VelocityFactor == g.VelocityFactor {
+			t.Fatalf("traits did not mutate with a rate of 1: %+v", c.Traits)
+		}
+		if c.EatRatio < 1 || c.GrowthInterval < 1 || c.DetectionRadius < 1 || c.VelocityFactor < 0 {
+			t.Fatalf("mutated traits out of bounds: %+v", c.Traits)
+		}
+		for j := range c.Weights {
+			if c.Weights[j] == g.Weights[j] {
+				t.Fatalf("weight %d did not mutate with a rate of 1", j)
+			}
+		}
+	}
+}
+
+func TestCrossoverGenes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	config := testConfig(false)
+	a, b := RandomGenome(rnd, config), RandomGenome(rnd, config)
+	b.DetectionRadius, b.VelocityFactor, b.GrowthInterval = a.DetectionRadius*2, a.VelocityFactor*2, a.GrowthInterval*2
+	fromA, fromB := 0, 0
+	for i := 0; i < 50; i++ {
+		child := Crossover(rnd, a, b)
+		switch child.DetectionRadius {
+		case a.DetectionRadius:
+			fromA++
+		case b.DetectionRadius:
+			fromB++
+		default:
+			t.Fatalf("detection radius %v comes from none of the parents", child.DetectionRadius)
+		}
+		for j, w := range child.Weights {
+			if w != a.Weights[j] && w != b.Weights[j] {
+				t.Fatalf("weight %d comes from none of the parents", j)
+			}
+		}
+	}
+	if fromA == 0 || fromB == 0 {
+		t.Errorf("genes come %d times from a and %d times from b, expected both", fromA, fromB)
+	}
+}
+
+func TestCrossoverTopologies(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	a := RandomGenome(rnd, testConfig(false))
+	other := testConfig(false)
+	other.Brain.Hidden = []int{3}
+	b := RandomGenome(rnd, other)
+	child := Crossover(rnd, a, b)
+	if !reflect.DeepEqual(child.Weights, a.Weights) {
+		t.Error("weights of parents of different topologies are mixed")
+	}
+	child.Weights[0]++
+	if child.Weights[0] == a.Weights[0] {
+		t.Error("child shares its weights with its parent")
+	}
+}
+
+func TestCrossoverNetworkNotShared(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	a := RandomGenome(rnd, testConfig(true))
+	b := RandomGenome(rnd, testConfig(false))
+	for _, child := range []Genome{Crossover(rnd, a, b), a.Copy(rnd, 0, 0)} {
+		if !reflect.DeepEqual(child.Network, a.Network) {
+			t.Fatal("child network differs from its single parent one")
+		}
+		child.Network.Connections[0].Weight++
+		if child.Network.Connections[0].Weight == a.Network.Connections[0].Weight {
+			t.Error("child shares its network with its parent")
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cell
+
+import (
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+// legacyRayInputs is the number of neural network inputs for each ray before cells could see pellets.
+const legacyRayInputs int = rayInputs - 1
+
+// State is the serializable state of a cell.
+type State struct {
+	ID             string          `json:"id"`
+	Size           float64         `json:"size"`
+	Energy         float64         `json:"energy"`
+	Genome         Genome          `json:"genome"`
+	Orientation    float64         `json:"orientation"`
+	Position       vector.Vector2D `json:"position"`
+	Velocity       vector.Vector2D `json:"velocity"`
+	MaxVelocity    float64         `json:"maxVelocity"`
+	Acceleration   vector.Vector2D `json:"acceleration"`
+	IsDead         bool            `json:"isDead"`
+	DeathCause     DeathCause      `json:"deathCause,omitempty"`
+	Death          int             `json:"death,omitempty"`
+	Predator       string          `json:"predator,omitempty"`
+	LastEnergyBurn int             `json:"lastEnergyBurn"`
+	LastGrowth     int             `json:"lastGrowth"`
+	Birth          int             `json:"birth"`
+	Kills          int             `json:"kills"`
+	Gathered       float64         `json:"gathered"`
+	Parent         string          `json:"parent,omitempty"`
+	Lineage        string          `json:"lineage"`
+}
+
+// State returns the cell state.
+func (c *Cell) State() State {
+	return State{
+		ID:             c.ID(),
+		Size:           c.size,
+		Energy:         c.energy,
+		Genome:         c.genome,
+		Orientation:    c.orientation,
+		Position:       c.position,
+		Velocity:       c.velocity,
+		MaxVelocity:    c.maxVelocity,
+		Acceleration:   c.acceleration,
+		IsDead:         c.isDead,
+		DeathCause:     c.deathCause,
+		Death:          c.death,
+		Predator:       c.Predator(),
+		LastEnergyBurn: c.lastEnergyBurn,
+		LastGrowth:     c.lastGrowth,
+		Birth:          c.birth,
+		Kills:          c.kills,
+		Gathered:       c.gathered,
+		Parent:         c.Parent(),
+		Lineage:        c.Lineage(),
+	}
+}
+
+// Restore creates a cell from a saved state.
+func Restore(rnd *rand.Rand, config *Config, s State, w, h int, env Environment) (*Cell, error) {
+	id, err := uuid.Parse(s.ID)
+	if err != nil {
+		return nil, err
+	}
+	// cells saved before lineages were recorded, up to version 2 snapshots, are their own ancestor
+	lineage := id
+	if s.Lineage != "" {
+		if lineage, err = uuid.Parse(s.Lineage); err != nil {
+			return nil, err
+		}
+	}
+	var parent uuid.UUID
+	if s.Parent != "" {
+		if parent, err = uuid.Parse(s.Parent); err != nil {
+			return nil, err
+		}
+	}
+	var predator uuid.UUID
+	if s.Predator != "" {
+		if predator, err = uuid.Parse(s.Predator); err != nil {
+			return nil, err
+		}
+	}
+	c := &Cell{
+		id:             id,
+		size:           s.Size,
+		energy:         s.Energy,
+		genome:         s.Genome,
+		config:         config,
+		rnd:            rnd,
+		orientation:    s.Orientation,
+		position:       s.Position,
+		velocity:       s.Velocity,
+		maxVelocity:    s.MaxVelocity,
+		acceleration:   s.Acceleration,
+		worldWidth:     float64(w),
+		worldHeight:    float64(h),
+		isDead:         s.IsDead,
+		deathCause:     s.DeathCause,
+		death:          s.Death,
+		predator:       predator,
+		lastEnergyBurn: s.LastEnergyBurn,
+		lastGrowth:     s.LastGrowth,
+		birth:          s.Birth,
+		kills:          s.Kills,
+		gathered:       s.Gathered,
+		parent:         parent,
+		lineage:        lineage,
+		env:            env,
+		neighbors:      []*Cell{},
+		controller:     newController(s.Genome),
+	}
+	return c, nil
+}
+
+// SetLegacyBrain sets the vision and brain of cells without any parent from the brain topology of a cell
+// saved before they were part of the config, when they were only given on the command line.
+func (c *Config) SetLegacyBrain(t brain.Topology) {
+	c.VisionRays = (t.Inputs() - 1) / legacyRayInputs
+	c.Brain.Hidden = append([]int{}, t.Layers[1:len(t.Layers)-1]...)
+	c.Brain.Activation = t.Hidden
+}
+
+// AddFoodInputs returns a genome saved before cells could see pellets, whose brain is given a null weight
+// for the pellet closeness of each ray, for it to be fed the sensors of current cells and act as it used to.
+func (g Genome) AddFoodInputs() Genome {
+	if !g.Brain.Enabled() || (g.Brain.Inputs()-1)%legacyRayInputs != 0 || len(g.Weights) != g.Brain.Weights() {
+		return g
+	}
+	inputs, neurons := g.Brain.Inputs(), g.Brain.Layers[1]
+	rays := (inputs - 1) / legacyRayInputs
+	weights := make([]float64, 0, len(g.Weights)+rays*neurons)
+	for j := 0; j < neurons; j++ {
+		neuron := g.Weights[j*(inputs+1) : (j+1)*(inputs+1)]
+		// energy
+		weights = append(weights, neuron[0])
+		for r := 0; r < rays; r++ {
+			// bigger cells, smaller cells and walls closeness, then pellets closeness before the target size
+			ray := neuron[1+r*legacyRayInputs : 1+(r+1)*legacyRayInputs]
+			weights = append(weights, ray[:3]...)
+			weights = append(weights, 0, ray[3])
+		}
+		// bias
+		weights = append(weights, neuron[inputs])
+	}
+	g.Weights = append(weights, g.Weights[neurons*(inputs+1):]...)
+	g.Brain.Layers = append([]int{1 + rays*rayInputs}, g.Brain.Layers[1:]...)
+	return g
+}
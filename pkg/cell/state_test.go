@@ -0,0 +1,67 @@
+package cell
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+func TestAddFoodInputs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	const rays = 3
+	legacy := brain.Topology{
+		Layers: []int{1 + rays*legacyRayInputs, 4, MotorCount},
+		Hidden: brain.ReLU,
+		Output: brain.Tanh,
+	}
+	g := Genome{Brain: legacy, Weights: brain.RandomWeights(legacy, rnd)}
+	weights := append([]float64{}, g.Weights...)
+
+	migrated := g.AddFoodInputs()
+	config := Config{VisionRays: rays}
+	config.SetLegacyBrain(legacy)
+	if err := config.CheckGenome(migrated); err != nil {
+		t.Fatal(err)
+	}
+	if config.Brain.Activation != brain.ReLU || len(config.Brain.Hidden) != 1 || config.Brain.Hidden[0] != 4 {
+		t.Errorf("brain config %+v, expected a hidden layer of 4 relu", config.Brain)
+	}
+	for i, w := range weights {
+		if g.Weights[i] != w {
+			t.Fatal("migrating the genome changed its weights")
+		}
+	}
+
+	old, err := brain.New(g.Brain, g.Weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := brain.New(migrated.Brain, migrated.Weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < 10; n++ {
+		inputs := []float64{rnd.Float64()}
+		withFood := []float64{inputs[0]}
+		for r := 0; r < rays; r++ {
+			ray := []float64{rnd.Float64(), rnd.Float64(), rnd.Float64(), rnd.Float64()}
+			inputs = append(inputs, ray...)
+			// the pellet closeness comes before the target size
+			withFood = append(withFood, ray[0], ray[1], ray[2], rnd.Float64(), ray[3])
+		}
+		want, err := old.Activate(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := current.Activate(withFood)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("migrated brain outputs %v, expected %v", got, want)
+			}
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"math"
 
 	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/food"
+	log "github.com/sirupsen/logrus"
 )
 
 // Accelerate set physical body acceleration.
@@ -11,50 +13,110 @@ func (c *Cell) Accelerate(acceleration vector.Vector2D) {
 	c.acceleration = acceleration
 }
 
-func (c *Cell) Update(counter int) {
-	c.neighbors = c.detect(c.position, 250)
+// Update computes the new cell state. It returns the cells born during the update, if any.
+func (c *Cell) Update(counter int) []*Cell {
+	// vision rays can not see further than detected neighbors
+	radius := math.Max(c.config.NeighborRadius, c.genome.DetectionRadius)
+	c.neighbors = c.env.Detect(c.position, radius)
+	c.pellets = c.env.DetectFood(c.position, radius)
+	c.meals = c.meals[:0]
 
-	if counter > c.lastEnergyBurn+150 {
-		c.energy -= 2
+	if counter > c.lastEnergyBurn+c.config.EnergyBurnInterval {
+		c.energy -= c.config.EnergyBurn
 		c.lastEnergyBurn = counter
 	}
 
-	if counter > c.lastGrowth+1000 {
-		c.energy -= 5
-		c.size += 5
+	if counter > c.lastGrowth+c.genome.GrowthInterval {
+		c.energy -= c.config.GrowthCost
+		c.size += c.config.GrowthStep
 		c.lastGrowth = counter
 	}
 
 	if c.energy <= 0 {
-		c.Kill()
-		return
+		c.Kill(counter, Starvation)
+		return nil
 	}
+
+	if c.config.MaxAge > 0 && c.Age(counter) >= c.config.MaxAge {
+		c.Kill(counter, OldAge)
+		return nil
+	}
+
+	if c.CanDivide() {
+		return c.Divide(counter)
+	}
+
 	predators := []vector.Vector2D{}
-	preyDistance := c.detectionRadius
-	preyPosition := vector.Vector2D{}
+	preyDistance := c.genome.DetectionRadius
+	var prey *Cell
 
 	for _, c1 := range c.neighbors {
 		// Don't compare to myself
-		if c1.ID() == c.ID() {
+		if c1 == c {
 			continue
 		}
 		// Eat smaller cells in the neighborood
-		if c.Intersect(c1) && c.Size() > c1.Size()*1.1 {
-			c.Eat(c1)
+		if c.Intersect(c1) && c.CanEat(c1) {
+			c.Eat(c1, counter)
 		}
-		dist := c.Position().Distance(c1.Position())
+		dist := c.distance(c1.Position())
 
 		// find the nearest prey in neighborood
-		if c.Size() > c1.Size() && dist < preyDistance {
+		if c.Diet().EatsCells() && c.Size() > c1.Size() && dist < preyDistance {
 			preyDistance = dist
-			preyPosition = c1.Position()
+			prey = c1
 		}
 		// record all the predators in neighborood
-		if c1.Size() > c.Size() {
+		if c1.Diet().EatsCells() && c1.Size() > c.Size() {
 			predators = append(predators, c1.Position())
 		}
 	}
 
+	foodDistance := c.genome.DetectionRadius
+	var pellet *food.Pellet
+	for _, p := range c.pellets {
+		if p.IsEaten() || !c.Diet().EatsFood() {
+			continue
+		}
+		// Eat pellets in contact
+		if c.Touches(p) {
+			c.EatFood(p)
+			continue
+		}
+		// find the nearest pellet in neighborood
+		if dist := c.distance(p.Position); dist < foodDistance {
+			foodDistance = dist
+			pellet = p
+		}
+	}
+
+	c.vision = c.see()
+
+	var acceleration vector.Vector2D
+	if c.controller != nil {
+		var err error
+		if acceleration, err = c.think(); err != nil {
+			// a controller not matching the cell sensors is dropped for the hard-coded rules
+			log.Errorf("cell %s controller dropped: %s", c.ID(), err.Error())
+			c.controller = nil
+			c.sensors = nil
+			c.layers = nil
+		}
+	}
+	if c.controller == nil {
+		acceleration = c.steer(predators, prey, pellet)
+	}
+
+	c.Accelerate(acceleration)
+	c.UpdateVelocity()
+	c.UpdateOrientation()
+	c.UpdatePosition()
+	return nil
+}
+
+// steer returns the acceleration given by hard-coded flee and chase rules.
+// Cells without any predator nor prey around go for the nearest pellet, if any.
+func (c *Cell) steer(predators []vector.Vector2D, prey *Cell, pellet *food.Pellet) vector.Vector2D {
 	acceleration := vector.Vector2D{
 		X: math.Cos(c.orientation),
 		Y: math.Sin(c.orientation),
@@ -64,27 +126,33 @@ func (c *Cell) Update(counter int) {
 		// if there is a predator in the neighborood, flee !
 		flee := c.avoid(predators)
 		acceleration.Add(flee)
-	} else if preyDistance < c.detectionRadius {
+	} else if prey != nil {
 		// else pursuit prey
-		chase := vector.Vector2D{
-			X: 0,
-			Y: 0,
-		}
-		d := c.Position().Distance(preyPosition)
-		diff := c.Position()
-		diff.Subtract(preyPosition)
-		diff.Normalize()
-		diff.Divide(d)
-		chase.Add(diff)
-
-		acceleration.Add(chase)
+		acceleration.Add(c.chase(prey.Position()))
+	} else if pellet != nil {
+		// else go eat
+		acceleration.Add(c.chase(pellet.Position))
 	}
 	// else continue in the same direction
 
-	c.Accelerate(acceleration)
-	c.UpdateVelocity()
-	c.UpdateOrientation()
-	c.UpdatePosition()
+	return acceleration
+}
+
+// chase returns an acceleration toward a target, stronger as the target is close.
+func (c *Cell) chase(target vector.Vector2D) vector.Vector2D {
+	chase := vector.Vector2D{
+		X: 0,
+		Y: 0,
+	}
+	d := c.distance(target)
+	if d == 0 {
+		return chase
+	}
+	diff := target.TorusDisplacement(c.Position(), c.worldWidth, c.worldHeight)
+	diff.Normalize()
+	diff.Divide(d)
+	chase.Add(diff)
+	return chase
 }
 
 func (c *Cell) avoid(predators []vector.Vector2D) vector.Vector2D {
@@ -94,20 +162,23 @@ func (c *Cell) avoid(predators []vector.Vector2D) vector.Vector2D {
 	}
 	cells := 0.0
 	for _, p := range predators {
+		d := c.distance(p)
+		// a predator exactly on the cell gives no direction to flee
+		if d == 0 {
+			continue
+		}
 		cells++
-		d := c.Position().Distance(p)
-		diff := c.Position()
-		diff.Subtract(p)
+		diff := p.TorusDisplacement(c.Position(), c.worldWidth, c.worldHeight)
 		diff.Normalize()
 		diff.Divide(d)
 		result.Add(diff)
 	}
-	if cells > 0 {
+	if cells > 0 && !result.IsNil() {
 		result.Divide(cells)
 		result.Normalize()
-		result.Multiply(cellMaxVelocity)
+		result.Multiply(c.config.MaxVelocity)
 		result.Subtract(c.Velocity())
-		result.Limit(cellMaxForce)
+		result.Limit(c.config.MaxForce)
 	}
 	return result
 }
@@ -142,15 +213,19 @@ func (c *Cell) UpdateOrientation() {
 // UpdatePosition compute new position.
 func (c *Cell) UpdatePosition() {
 	c.position.Add(c.velocity)
+	c.wrapPosition()
+}
 
-	if c.position.X >= c.screenWidth-1.0 {
+// wrapPosition makes a cell leaving the world through an edge enter back from the opposite one.
+func (c *Cell) wrapPosition() {
+	if c.position.X >= c.worldWidth-1.0 {
 		c.position.X = 0
 	} else if c.position.X < 0 {
-		c.position.X = c.screenWidth - 1.0
+		c.position.X = c.worldWidth - 1.0
 	}
-	if c.position.Y >= c.screenHeight-1.0 {
+	if c.position.Y >= c.worldHeight-1.0 {
 		c.position.Y = 0
 	} else if c.position.Y < 0 {
-		c.position.Y = c.screenHeight - 1.0
+		c.position.Y = c.worldHeight - 1.0
 	}
 }
@@ -0,0 +1,150 @@
+package cell
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+// RayKind is the kind of target hit by a vision ray.
+type RayKind int
+
+const (
+	// RayNone is a ray hitting nothing in the detection radius.
+	RayNone RayKind = iota
+	// RayBigger is a ray hitting a bigger cell.
+	RayBigger
+	// RaySmaller is a ray hitting a smaller cell.
+	RaySmaller
+	// RayWall is a ray crossing a world edge without hitting any cell nor pellet.
+	RayWall
+	// RayFood is a ray hitting a pellet.
+	RayFood
+)
+
+// rayInputs is the number of neural network inputs for each ray.
+const rayInputs int = 5
+
+// Ray is what a cell sees in a given direction.
+type Ray struct {
+	// Angle is the absolute direction of the ray (radian).
+	Angle float64
+	// Distance is the distance to the target, or the detection radius if nothing is hit.
+	Distance float64
+	// Size is the size of the target cell or pellet.
+	Size float64
+	// Kind is the kind of target.
+	Kind RayKind
+	// Wall is the distance to the world edge crossed by the ray, or the detection radius if it stays in the world.
+	Wall float64
+}
+
+// rayAngle returns the direction of the i-th vision ray.
+func (c *Cell) rayAngle(i int) float64 {
+	if c.config.VisionRays == 1 {
+		return c.orientation
+	}
+	fov := c.config.fieldOfView()
+	return c.orientation - fov/2 + float64(i)*fov/float64(c.config.VisionRays-1)
+}
+
+// see casts vision rays across the field of view and returns the nearest target hit by each of them.
+// The world being a torus, rays run their full length across its edges, which are reported as walls.
+func (c *Cell) see() []Ray {
+	radius := c.genome.DetectionRadius
+	rays := make([]Ray, c.config.VisionRays)
+	for i := range rays {
+		angle := c.rayAngle(i)
+		direction := vector.Vector2D{X: math.Cos(angle), Y: math.Sin(angle)}
+		ray := Ray{Angle: angle, Distance: radius, Kind: RayNone, Wall: math.Min(c.edgeDistance(direction), radius)}
+
+		for _, c1 := range c.neighbors {
+			if c1 == c || c1.IsDead() {
+				continue
+			}
+			if dist, ok := c.rayHit(direction, c1.position, c1.size); ok && dist < ray.Distance {
+				ray.Distance = dist
+				ray.Size = c1.size
+				ray.Kind = RaySmaller
+				if c1.size > c.size {
+					ray.Kind = RayBigger
+				}
+			}
+		}
+		for _, p := range c.pellets {
+			if p.IsEaten() {
+				continue
+			}
+			if dist, ok := c.rayHit(direction, p.Position, p.Size); ok && dist < ray.Distance {
+				ray.Distance = dist
+				ray.Size = p.Size
+				ray.Kind = RayFood
+			}
+		}
+		if ray.Kind == RayNone && ray.Wall < radius {
+			ray.Distance = ray.Wall
+			ray.Kind = RayWall
+		}
+		rays[i] = ray
+	}
+	return rays
+}
+
+// rayHit returns the distance along a ray cast in a given direction to a circle, and false if the ray misses it.
+func (c *Cell) rayHit(direction, center vector.Vector2D, radius float64) (float64, bool) {
+	// ray-circle intersection
+	m := c.displacement(center)
+	t := m.X*direction.X + m.Y*direction.Y
+	if t < 0 {
+		return 0, false
+	}
+	perpSq := m.MagnitudeSquared() - t*t
+	if perpSq > radius*radius {
+		return 0, false
+	}
+	return math.Max(t-math.Sqrt(radius*radius-perpSq), 0), true
+}
+
+// edgeDistance returns the distance to the world edge in a given direction.
+func (c *Cell) edgeDistance(direction vector.Vector2D) float64 {
+	dist := math.Inf(1)
+	if direction.X > 0 {
+		dist = math.Min(dist, (c.worldWidth-c.position.X)/direction.X)
+	} else if direction.X < 0 {
+		dist = math.Min(dist, -c.position.X/direction.X)
+	}
+	if direction.Y > 0 {
+		dist = math.Min(dist, (c.worldHeight-c.position.Y)/direction.Y)
+	} else if direction.Y < 0 {
+		dist = math.Min(dist, -c.position.Y/direction.Y)
+	}
+	return dist
+}
+
+// Vision returns what the cell saw during its last update.
+func (c *Cell) Vision() []Ray {
+	return c.vision
+}
+
+// VisionInputs encodes vision rays as neural network inputs. For each ray, the closeness (1 when touching,
+// 0 at the detection radius) is given for bigger cells, smaller cells, walls and pellets, followed by the
+// target size relative to the cell size. The wall closeness is given even when a target is seen across it.
+func (c *Cell) VisionInputs() []float64 {
+	inputs := make([]float64, 0, len(c.vision)*rayInputs)
+	for _, ray := range c.vision {
+		closeness := 1 - ray.Distance/c.genome.DetectionRadius
+		values := make([]float64, rayInputs)
+		switch ray.Kind {
+		case RayBigger:
+			values[0] = closeness
+		case RaySmaller:
+			values[1] = closeness
+		case RayFood:
+			values[3] = closeness
+		}
+		values[2] = 1 - ray.Wall/c.genome.DetectionRadius
+		values[4] = ray.Size / c.size
+		inputs = append(inputs, values...)
+	}
+	return inputs
+}
@@ -0,0 +1,60 @@
+package cell
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+func TestSeeAcrossWalls(t *testing.T) {
+	config := testConfig(false)
+	config.VisionRays = 1
+	rnd := rand.New(rand.NewSource(7))
+	genome := RandomGenome(rnd, config)
+	genome.DetectionRadius = 100
+	newAt := func(x, y, size float64) *Cell {
+		return newCell(rnd, config, vector.Vector2D{X: x, Y: y}, size, config.InitialEnergy, genome, 640, 480, nil)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		x         float64
+		neighbor  *Cell
+		kind      RayKind
+		distance  float64
+		wall      float64
+		closeness []float64
+	}{
+		{"nothing", 320, nil, RayNone, 100, 100, []float64{0, 0, 0, 0}},
+		{"wall", 630, nil, RayWall, 10, 10, []float64{0, 0, 0.9, 0}},
+		{"smaller cell across the wall", 630, newAt(20, 240, 5), RaySmaller, 25, 10, []float64{0, 0.75, 0.9, 0}},
+		{"bigger cell before the wall", 560, newAt(600, 240, 20), RayBigger, 20, 80, []float64{0.8, 0, 0.2, 0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newAt(tc.x, 240, 10)
+			c.orientation = 0
+			c.neighbors = []*Cell{c}
+			if tc.neighbor != nil {
+				c.neighbors = append(c.neighbors, tc.neighbor)
+			}
+			c.vision = c.see()
+			ray := c.Vision()[0]
+			if ray.Kind != tc.kind || math.Abs(ray.Distance-tc.distance) > 1e-9 || math.Abs(ray.Wall-tc.wall) > 1e-9 {
+				t.Errorf("ray sees %v at %v with a wall at %v, expected %v at %v with a wall at %v",
+					ray.Kind, ray.Distance, ray.Wall, tc.kind, tc.distance, tc.wall)
+			}
+			inputs := c.VisionInputs()
+			if len(inputs) != rayInputs {
+				t.Fatalf("%d vision inputs, expected %d", len(inputs), rayInputs)
+			}
+			for i, v := range tc.closeness {
+				if math.Abs(inputs[i]-v) > 1e-9 {
+					t.Errorf("vision inputs %v, expected closeness %v", inputs, tc.closeness)
+					break
+				}
+			}
+		})
+	}
+}
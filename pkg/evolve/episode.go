@@ -0,0 +1,93 @@
+package evolve
+
+import (
+	"math/rand"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/neat"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// tracker accumulates the results of the lineages founded by the evaluated genomes.
+type tracker struct {
+	ticks   int
+	results map[string]*Result
+	living  map[string]int
+}
+
+// Born counts the offspring of the evaluated lineages.
+func (t *tracker) Born(w *sim.World, c *cell.Cell) {
+	r, ok := t.results[c.Lineage()]
+	if !ok {
+		return
+	}
+	t.living[c.Lineage()]++
+	if c.Lineage() != c.ID() {
+		r.Offspring++
+	}
+}
+
+// Died accounts for the achievements of a dead cell of an evaluated lineage.
+func (t *tracker) Died(w *sim.World, c *cell.Cell) {
+	r, ok := t.results[c.Lineage()]
+	if !ok {
+		return
+	}
+	r.Energy += c.Gathered()
+	r.Kills += c.Kills()
+	t.living[c.Lineage()]--
+	if t.living[c.Lineage()] == 0 {
+		r.Survival = w.Counter()
+	}
+}
+
+// finish accounts for the achievements of the cells still living at the end of an episode.
+func (t *tracker) finish(w *sim.World) {
+	for _, c := range w.Cells() {
+		if r, ok := t.results[c.Lineage()]; ok && !c.IsDead() {
+			r.Energy += c.Gathered()
+			r.Kills += c.Kills()
+			r.Survival = t.ticks
+		}
+	}
+}
+
+// episode runs a headless world during a given number of ticks, each genome founding a lineage
+// at a random position, and returns the results of the lineages in the genomes order. NEAT mutations
+// are registered in innovations.
+func episode(config sim.Config, seed int64, ticks int, genomes []cell.Genome, innovations *neat.Innovations) []Result {
+	w := sim.New(config, seed)
+	// offspring born in the episode mutate their networks consistently with the genomes evaluated
+	w.SetInnovations(innovations)
+	rnd := rand.New(rand.NewSource(seed))
+	t := &tracker{
+		ticks:   ticks,
+		results: map[string]*Result{},
+		living:  map[string]int{},
+	}
+	w.AddObserver(t)
+
+	lineages := make([]string, len(genomes))
+	for i, g := range genomes {
+		pos := vector.Vector2D{X: rnd.Float64() * float64(w.Width), Y: rnd.Float64() * float64(w.Height)}
+		c := w.SpawnGenome(pos, g)
+		lineages[i] = c.Lineage()
+		t.results[c.Lineage()] = &Result{}
+		t.living[c.Lineage()] = 1
+	}
+
+	for w.Counter() < ticks {
+		if err := w.Step(); err != nil {
+			// extinct world
+			break
+		}
+	}
+	t.finish(w)
+
+	results := make([]Result, len(genomes))
+	for i, lineage := range lineages {
+		results[i] = *t.results[lineage]
+	}
+	return results
+}
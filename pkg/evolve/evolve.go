@@ -0,0 +1,252 @@
+// Package evolve runs generational genetic algorithm experiments: a population of genomes is evaluated
+// in fixed-length headless episodes, and the best ones breed the next generation.
+package evolve
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/neat"
+	"github.com/jtbonhomme/golife/pkg/sim"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config holds the parameters of an experiment.
+type Config struct {
+	// Population is the number of genomes of each generation.
+	Population int `json:"population"`
+	// Generations is the number of generations to evaluate.
+	Generations int `json:"generations"`
+	// Ticks is the length of an episode.
+	Ticks int `json:"ticks"`
+	// Episodes is the number of episodes a generation is evaluated in, fitnesses being averaged.
+	Episodes int `json:"episodes"`
+	// Elites is the number of best genomes copied unchanged to the next generation.
+	Elites int `json:"elites"`
+	// TournamentSize is the number of genomes competing to be selected as a parent.
+	TournamentSize int `json:"tournamentSize"`
+	// CrossoverRate is the probability for an offspring to mix two parents, rather than copying one.
+	CrossoverRate float64 `json:"crossoverRate"`
+	// World holds the parameters of the episodes worlds, and the mutation parameters of genomes.
+	World sim.Config `json:"world"`
+}
+
+// DefaultConfig returns the default experiment parameters.
+func DefaultConfig() Config {
+	return Config{
+		Population:     30,
+		Generations:    20,
+		Ticks:          3000,
+		Episodes:       1,
+		Elites:         2,
+		TournamentSize: 3,
+		CrossoverRate:  0.7,
+		World:          sim.DefaultConfig(),
+	}
+}
+
+// Validate checks the parameters are consistent.
+func (c Config) Validate() error {
+	if c.Population <= 0 || c.Generations <= 0 || c.Ticks <= 0 || c.Episodes <= 0 {
+		return fmt.Errorf("population, generations, ticks and episodes must be positive")
+	}
+	if c.Elites < 0 || c.Elites > c.Population {
+		return fmt.Errorf("elites (%d) must be in [0, population]", c.Elites)
+	}
+	if c.TournamentSize <= 0 {
+		return fmt.Errorf("tournamentSize must be positive, got %d", c.TournamentSize)
+	}
+	if c.CrossoverRate < 0 || c.CrossoverRate > 1 {
+		return fmt.Errorf("crossoverRate (%v) must be in [0, 1]", c.CrossoverRate)
+	}
+	return c.World.Validate()
+}
+
+// Individual is an evaluated genome.
+type Individual struct {
+	Genome  cell.Genome `json:"genome"`
+	Fitness float64     `json:"fitness"`
+}
+
+// Generation sums up the evaluation of a generation.
+type Generation struct {
+	Index int     `json:"index"`
+	Best  float64 `json:"best"`
+	Mean  float64 `json:"mean"`
+	// Species is the number of species of NEAT networks, 0 without NEAT.
+	Species int `json:"species,omitempty"`
+	// Champion is the best individual of the generation.
+	Champion Individual `json:"champion"`
+}
+
+// Runner runs an experiment.
+type Runner struct {
+	config      Config
+	fitness     Fitness
+	rnd         *rand.Rand
+	innovations *neat.Innovations
+}
+
+// New creates a runner scoring genomes with a fitness function. Two runners created with the same
+// config and seed run identical experiments.
+func New(config Config, fitness Fitness, seed int64) *Runner {
+	return &Runner{
+		config:      config,
+		fitness:     fitness,
+		rnd:         rand.New(rand.NewSource(seed)),
+		innovations: config.World.Cell.NewInnovations(),
+	}
+}
+
+// Run evaluates all generations, calling report after each one, and returns the best individual
+// ever evaluated.
+func (r *Runner) Run(report func(Generation)) Individual {
+	population := make([]cell.Genome, r.config.Population)
+	for i := range population {
+		population[i] = cell.RandomGenome(r.rnd, &r.config.World.Cell)
+	}
+
+	var champion Individual
+	for g := 0; g < r.config.Generations; g++ {
+		individuals := r.evaluate(population)
+		sort.SliceStable(individuals, func(i, j int) bool {
+			return individuals[i].Fitness > individuals[j].Fitness
+		})
+
+		mean := 0.0
+		for _, ind := range individuals {
+			mean += ind.Fitness
+		}
+		scores, species := r.share(individuals)
+		generation := Generation{
+			Index:    g,
+			Best:     individuals[0].Fitness,
+			Mean:     mean / float64(len(individuals)),
+			Species:  species,
+			Champion: individuals[0],
+		}
+		log.WithFields(log.Fields{
+			"generation": g,
+			"best":       generation.Best,
+			"mean":       generation.Mean,
+			"species":    generation.Species,
+		}).Info("generation evaluated")
+		if report != nil {
+			report(generation)
+		}
+		if g == 0 || generation.Best > champion.Fitness {
+			champion = generation.Champion
+		}
+		population = r.breed(individuals, scores)
+	}
+	return champion
+}
+
+// evaluate scores the genomes of a generation, averaging their fitness over the episodes.
+func (r *Runner) evaluate(population []cell.Genome) []Individual {
+	individuals := make([]Individual, len(population))
+	for i, g := range population {
+		individuals[i].Genome = g
+	}
+	for e := 0; e < r.config.Episodes; e++ {
+		results := episode(r.config.World, r.rnd.Int63(), r.config.Ticks, population, r.innovations)
+		for i, result := range results {
+			individuals[i].Fitness += r.fitness(result) / float64(r.config.Episodes)
+		}
+	}
+	return individuals
+}
+
+// share returns the scores individuals are selected on, and their number of species. Without NEAT,
+// scores are fitnesses. With NEAT, genomes are grouped in species and share their fitness within
+// their species, for new topologies to survive until their weights are tuned.
+func (r *Runner) share(individuals []Individual) ([]float64, int) {
+	scores := make([]float64, len(individuals))
+	for i, ind := range individuals {
+		scores[i] = ind.Fitness
+	}
+	config := r.config.World.Cell.Brain.NEAT
+	if config == nil {
+		return scores, 0
+	}
+	networks := []*neat.Genome{}
+	for _, ind := range individuals {
+		if ind.Genome.Network == nil {
+			return scores, 0
+		}
+		networks = append(networks, ind.Genome.Network)
+	}
+	species := neat.Speciate(networks, *config)
+	count := 0
+	for _, s := range species {
+		if s >= count {
+			count = s + 1
+		}
+	}
+	return neat.ShareFitness(scores, species), count
+}
+
+// breed returns the next generation from individuals sorted by decreasing fitness:
+// the elites are kept, and the others are offspring of parents selected by tournament on their scores.
+func (r *Runner) breed(individuals []Individual, scores []float64) []cell.Genome {
+	next := make([]cell.Genome, 0, len(individuals))
+	for i := 0; i < r.config.Elites; i++ {
+		next = append(next, individuals[i].Genome)
+	}
+	config := &r.config.World.Cell
+	for len(next) < len(individuals) {
+		a := r.tournament(scores)
+		child := individuals[a].Genome
+		if r.rnd.Float64() < r.config.CrossoverRate {
+			b := r.tournament(scores)
+			// the fitter parent comes first
+			if individuals[b].Fitness > individuals[a].Fitness {
+				a, b = b, a
+			}
+			child = cell.Crossover(r.rnd, individuals[a].Genome, individuals[b].Genome)
+		}
+		next = append(next, child.Mutate(r.rnd, config, r.innovations))
+	}
+	return next
+}
+
+// tournament returns the index of the best scored of a few individuals drawn at random.
+func (r *Runner) tournament(scores []float64) int {
+	best := r.rnd.Intn(len(scores))
+	for i := 1; i < r.config.TournamentSize; i++ {
+		if j := r.rnd.Intn(len(scores)); scores[j] > scores[best] {
+			best = j
+		}
+	}
+	return best
+}
+
+// SaveIndividual writes an individual to a JSON file.
+func SaveIndividual(path string, ind Individual) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ind); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadIndividual reads an individual from a JSON file.
+func LoadIndividual(path string) (Individual, error) {
+	var ind Individual
+	f, err := os.Open(path)
+	if err != nil {
+		return ind, err
+	}
+	defer f.Close()
+	return ind, json.NewDecoder(f).Decode(&ind)
+}
@@ -0,0 +1,179 @@
+package evolve
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// testConfig returns the parameters of a small experiment, evolving brains.
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Population = 6
+	config.Generations = 3
+	config.Ticks = 300
+	config.World.Width, config.World.Height = 320, 240
+	config.World.Population = 10
+	config.World.Cell.Brain.Hidden = []int{4}
+	return config
+}
+
+// run runs an experiment and returns its generations, as JSON to be compared, and its champion.
+func run(t *testing.T, config Config, seed int64) (string, Individual) {
+	t.Helper()
+	generations := []Generation{}
+	champion := New(config, Energy, seed).Run(func(g Generation) {
+		generations = append(generations, g)
+	})
+	if len(generations) != config.Generations {
+		t.Fatalf("%d generations reported, expected %d", len(generations), config.Generations)
+	}
+	best := generations[0].Best
+	for _, g := range generations {
+		if g.Best < g.Mean {
+			t.Errorf("generation %d: best %v under the mean %v", g.Index, g.Best, g.Mean)
+		}
+		if g.Best > best {
+			best = g.Best
+		}
+	}
+	if champion.Fitness != best {
+		t.Errorf("champion fitness %v, expected the best of all generations %v", champion.Fitness, best)
+	}
+	b, err := json.Marshal(generations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), champion
+}
+
+func TestRunReproducible(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		neat bool
+	}{
+		{"brain", false},
+		{"neat", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := testConfig()
+			if tc.neat {
+				neatConfig := neat.DefaultConfig()
+				config.World.Cell.Brain.NEAT = &neatConfig
+			}
+			generations, champion := run(t, config, 5)
+			again, championAgain := run(t, config, 5)
+			if generations != again || !reflect.DeepEqual(champion, championAgain) {
+				t.Error("experiments of the same seed differ")
+			}
+			if other, _ := run(t, config, 6); other == generations {
+				t.Error("experiments of different seeds are identical")
+			}
+		})
+	}
+}
+
+// individuals returns individuals of distinct genomes, sorted by decreasing fitness.
+func individuals(config *cell.Config, n int) []Individual {
+	rnd := rand.New(rand.NewSource(1))
+	inds := make([]Individual, n)
+	for i := range inds {
+		inds[i] = Individual{Genome: cell.RandomGenome(rnd, config), Fitness: float64(n - i)}
+	}
+	return inds
+}
+
+func TestElitism(t *testing.T) {
+	config := testConfig()
+	config.Elites = 2
+	r := New(config, Energy, 1)
+	inds := individuals(&config.World.Cell, config.Population)
+	scores := make([]float64, len(inds))
+	for i, ind := range inds {
+		scores[i] = ind.Fitness
+	}
+	next := r.breed(inds, scores)
+	if len(next) != len(inds) {
+		t.Fatalf("%d genomes bred, expected %d", len(next), len(inds))
+	}
+	for i := 0; i < config.Elites; i++ {
+		if !reflect.DeepEqual(next[i], inds[i].Genome) {
+			t.Errorf("elite %d changed", i)
+		}
+	}
+	for i := config.Elites; i < len(next); i++ {
+		for _, ind := range inds {
+			if reflect.DeepEqual(next[i].Weights, ind.Genome.Weights) {
+				t.Errorf("offspring %d has the brain of a parent, expected it to be mutated", i)
+			}
+		}
+	}
+}
+
+func TestTournament(t *testing.T) {
+	scores := []float64{1, 5, 3, 2, 4}
+	config := testConfig()
+
+	config.TournamentSize = 50
+	r := New(config, Energy, 1)
+	for i := 0; i < 100; i++ {
+		if best := r.tournament(scores); best != 1 {
+			t.Fatalf("large tournament selected %d, expected the best scored 1", best)
+		}
+	}
+
+	// smaller tournaments select the better scored more often
+	config.TournamentSize = 2
+	r = New(config, Energy, 1)
+	selected := make([]int, len(scores))
+	for i := 0; i < 1000; i++ {
+		selected[r.tournament(scores)]++
+	}
+	for i := range scores {
+		for j := range scores {
+			if scores[i] > scores[j] && selected[i] <= selected[j] {
+				t.Errorf("selections %v do not follow scores %v", selected, scores)
+			}
+		}
+	}
+}
+
+func TestShare(t *testing.T) {
+	config := testConfig()
+	inds := individuals(&config.World.Cell, 3)
+	scores, species := New(config, Energy, 1).share(inds)
+	if species != 0 || !reflect.DeepEqual(scores, []float64{3, 2, 1}) {
+		t.Errorf("scores %v of %d species without NEAT, expected the fitnesses", scores, species)
+	}
+
+	// identical networks share a species, distinct ones do not
+	neatConfig := neat.DefaultConfig()
+	neatConfig.CompatibilityThreshold = 1e-9
+	config.World.Cell.Brain.NEAT = &neatConfig
+	inds = individuals(&config.World.Cell, 3)
+	inds[1].Genome.Network = inds[0].Genome.Network.Copy()
+	scores, species = New(config, Energy, 1).share(inds)
+	if species != 2 || !reflect.DeepEqual(scores, []float64{1.5, 1, 1}) {
+		t.Errorf("scores %v of %d species, expected [1.5 1 1] of 2 species", scores, species)
+	}
+}
+
+func TestParseFitness(t *testing.T) {
+	r := Result{Survival: 10, Energy: 20, Kills: 3}
+	for name, expected := range map[string]float64{"survival": 10, "energy": 20, "kills": 3} {
+		f, err := ParseFitness(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f(r) != expected {
+			t.Errorf("%s fitness %v, expected %v", name, f(r), expected)
+		}
+	}
+	if _, err := ParseFitness("speed"); err == nil {
+		t.Error("unknown fitness parsed")
+	}
+}
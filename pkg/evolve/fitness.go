@@ -0,0 +1,62 @@
+package evolve
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Result is what the lineage founded by a genome achieved during an episode.
+type Result struct {
+	// Survival is the number of ticks during which the lineage had living cells.
+	Survival int `json:"survival"`
+	// Energy is the energy gained by eating by all the cells of the lineage.
+	Energy float64 `json:"energy"`
+	// Kills is the number of cells eaten by all the cells of the lineage.
+	Kills int `json:"kills"`
+	// Offspring is the number of cells born in the lineage.
+	Offspring int `json:"offspring"`
+}
+
+// Fitness scores the result of a genome, the higher the better.
+type Fitness func(Result) float64
+
+// Survival scores a genome by the survival time of its lineage.
+func Survival(r Result) float64 {
+	return float64(r.Survival)
+}
+
+// Energy scores a genome by the energy gathered by its lineage.
+func Energy(r Result) float64 {
+	return r.Energy
+}
+
+// Kills scores a genome by the number of cells eaten by its lineage.
+func Kills(r Result) float64 {
+	return float64(r.Kills)
+}
+
+// fitnesses are the fitness functions available by name.
+var fitnesses = map[string]Fitness{
+	"survival": Survival,
+	"energy":   Energy,
+	"kills":    Kills,
+}
+
+// ParseFitness returns the fitness function of a given name.
+func ParseFitness(name string) (Fitness, error) {
+	f, ok := fitnesses[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fitness %q, expected one of %v", name, FitnessNames())
+	}
+	return f, nil
+}
+
+// FitnessNames returns the names of the available fitness functions.
+func FitnessNames() []string {
+	names := make([]string, 0, len(fitnesses))
+	for name := range fitnesses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,135 @@
+// Package food describes the plant pellets growing in the world, eaten by cells to restore their energy.
+package food
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+// Pellet is a piece of food lying in the world.
+type Pellet struct {
+	Position vector.Vector2D `json:"position"`
+	// Size is the radius of the pellet.
+	Size float64 `json:"size"`
+	// Energy is the energy given to the cell eating the pellet.
+	Energy float64 `json:"energy"`
+	eaten  bool
+}
+
+// IsEaten returns true if the pellet has been eaten.
+func (p *Pellet) IsEaten() bool {
+	return p.eaten
+}
+
+// Consume marks the pellet as eaten and returns its energy.
+func (p *Pellet) Consume() float64 {
+	p.eaten = true
+	return p.Energy
+}
+
+// Region is a fertile disc of the world where pellets grow.
+type Region struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Radius float64 `json:"radius"`
+}
+
+// Config holds the parameters of pellets growth.
+type Config struct {
+	// Rate is the average number of pellets growing at each tick.
+	Rate float64 `json:"rate"`
+	// Max is the number of pellets above which none grows anymore.
+	Max int `json:"max"`
+	// Size and Energy are the radius and energy of new pellets.
+	Size   float64 `json:"size"`
+	Energy float64 `json:"energy"`
+	// Regions are the fertile regions pellets grow in, anywhere in the world if empty.
+	Regions []Region `json:"regions"`
+}
+
+// DefaultConfig returns the default pellets growth parameters.
+func DefaultConfig() Config {
+	return Config{
+		Rate:    0.1,
+		Max:     150,
+		Size:    3,
+		Energy:  10,
+		Regions: []Region{},
+	}
+}
+
+// Enabled returns true if pellets ever grow.
+func (c Config) Enabled() bool {
+	return c.Rate > 0 && c.Max > 0
+}
+
+// Validate checks the parameters are consistent.
+func (c Config) Validate() error {
+	if c.Rate < 0 {
+		return fmt.Errorf("rate must not be negative, got %v", c.Rate)
+	}
+	if c.Max < 0 {
+		return fmt.Errorf("max must not be negative, got %d", c.Max)
+	}
+	if c.Enabled() && (c.Size <= 0 || c.Energy <= 0) {
+		return fmt.Errorf("size (%v) and energy (%v) must be positive", c.Size, c.Energy)
+	}
+	for i, r := range c.Regions {
+		if r.Radius <= 0 {
+			return fmt.Errorf("regions[%d].radius must be positive, got %v", i, r.Radius)
+		}
+	}
+	return nil
+}
+
+// Grow returns the pellets growing during a tick in a world of given dimensions already holding
+// a given number of pellets, drawing their number and positions from rnd.
+func (c Config) Grow(rnd *rand.Rand, width, height, existing int) []*Pellet {
+	if !c.Enabled() {
+		return nil
+	}
+	n := int(c.Rate)
+	if rnd.Float64() < c.Rate-float64(n) {
+		n++
+	}
+	if n > c.Max-existing {
+		n = c.Max - existing
+	}
+	if n <= 0 {
+		return nil
+	}
+	pellets := make([]*Pellet, n)
+	for i := range pellets {
+		pellets[i] = &Pellet{
+			Position: c.position(rnd, float64(width), float64(height)),
+			Size:     c.Size,
+			Energy:   c.Energy,
+		}
+	}
+	return pellets
+}
+
+// position draws a random position in a fertile region, wrapped around the world edges.
+func (c Config) position(rnd *rand.Rand, width, height float64) vector.Vector2D {
+	if len(c.Regions) == 0 {
+		return vector.Vector2D{X: rnd.Float64() * width, Y: rnd.Float64() * height}
+	}
+	r := c.Regions[rnd.Intn(len(c.Regions))]
+	// uniform distribution over the disc
+	dist := r.Radius * math.Sqrt(rnd.Float64())
+	angle := rnd.Float64() * 2 * math.Pi
+	wrap := func(v, size float64) float64 {
+		v = math.Mod(v, size)
+		if v < 0 {
+			v += size
+		}
+		return v
+	}
+	return vector.Vector2D{
+		X: wrap(r.X+dist*math.Cos(angle), width),
+		Y: wrap(r.Y+dist*math.Sin(angle), height),
+	}
+}
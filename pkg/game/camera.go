@@ -0,0 +1,144 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+const (
+	maxZoom = 8.0
+	// zoomStep is the zoom factor applied by a mouse wheel notch.
+	zoomStep = 1.1
+	// panSpeed is the number of screen pixels the camera moves by per frame with the arrow keys.
+	panSpeed = 10.0
+)
+
+// camera is the viewport over the world, mapping world coordinates to screen coordinates.
+type camera struct {
+	// position is the world location displayed at the top left corner of the screen.
+	position     vector.Vector2D
+	zoom         float64
+	width        float64
+	height       float64
+	worldWidth   float64
+	worldHeight  float64
+	dragging     bool
+	dragX, dragY int
+	// dragged is the distance covered by the cursor since the left button was pressed.
+	dragged float64
+}
+
+func newCamera(worldWidth, worldHeight, width, height int) *camera {
+	c := &camera{
+		zoom:        1,
+		worldWidth:  float64(worldWidth),
+		worldHeight: float64(worldHeight),
+	}
+	c.resize(width, height)
+	return c
+}
+
+// resize sets the size of the screen, keeping the zoom level within its bounds.
+func (c *camera) resize(width, height int) {
+	c.width, c.height = float64(width), float64(height)
+	c.zoom = math.Max(c.minZoom(), math.Min(maxZoom, c.zoom))
+	c.clamp()
+}
+
+// minZoom is the zoom level at which the whole world fits the screen.
+func (c *camera) minZoom() float64 {
+	return math.Min(1, math.Min(c.width/c.worldWidth, c.height/c.worldHeight))
+}
+
+// clamp keeps the viewport inside the world, or centers the world when it is smaller than the viewport.
+func (c *camera) clamp() {
+	clampAxis := func(p, visible, size float64) float64 {
+		if visible >= size {
+			return (size - visible) / 2
+		}
+		return math.Max(0, math.Min(size-visible, p))
+	}
+	c.position.X = clampAxis(c.position.X, c.width/c.zoom, c.worldWidth)
+	c.position.Y = clampAxis(c.position.Y, c.height/c.zoom, c.worldHeight)
+}
+
+// toScreen returns the screen coordinates of a world location.
+func (c *camera) toScreen(p vector.Vector2D) vector.Vector2D {
+	return vector.Vector2D{
+		X: (p.X - c.position.X) * c.zoom,
+		Y: (p.Y - c.position.Y) * c.zoom,
+	}
+}
+
+// toWorld returns the world location displayed at given screen coordinates.
+func (c *camera) toWorld(x, y float64) vector.Vector2D {
+	return vector.Vector2D{
+		X: c.position.X + x/c.zoom,
+		Y: c.position.Y + y/c.zoom,
+	}
+}
+
+// visible tells if a disc of the world is at least partially displayed.
+func (c *camera) visible(p vector.Vector2D, radius float64) bool {
+	s := c.toScreen(p)
+	r := radius * c.zoom
+	return s.X+r >= 0 && s.Y+r >= 0 && s.X-r <= c.width && s.Y-r <= c.height
+}
+
+// zoomAt multiplies the zoom level by factor, keeping the world location under (x,y) in place.
+func (c *camera) zoomAt(factor, x, y float64) {
+	anchor := c.toWorld(x, y)
+	c.zoom = math.Max(c.minZoom(), math.Min(maxZoom, c.zoom*factor))
+	c.position = vector.Vector2D{X: anchor.X - x/c.zoom, Y: anchor.Y - y/c.zoom}
+	c.clamp()
+}
+
+// pan moves the camera by a given number of screen pixels.
+func (c *camera) pan(dx, dy float64) {
+	c.position.X += dx / c.zoom
+	c.position.Y += dy / c.zoom
+	c.clamp()
+}
+
+// center moves the camera for a world location to be at the center of the screen.
+func (c *camera) center(p vector.Vector2D) {
+	c.position = vector.Vector2D{X: p.X - c.width/c.zoom/2, Y: p.Y - c.height/c.zoom/2}
+	c.clamp()
+}
+
+// handleInput zooms with the mouse wheel, and pans with the arrow keys or by dragging with the left button.
+func (c *camera) handleInput() {
+	x, y := ebiten.CursorPosition()
+	if _, dy := ebiten.Wheel(); dy != 0 {
+		c.zoomAt(math.Pow(zoomStep, dy), float64(x), float64(y))
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		c.pan(-panSpeed, 0)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		c.pan(panSpeed, 0)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		c.pan(0, -panSpeed)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		c.pan(0, panSpeed)
+	}
+
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		c.dragging = false
+		return
+	}
+	if !c.dragging {
+		c.dragging = true
+		c.dragged = 0
+	} else {
+		dx, dy := float64(c.dragX-x), float64(c.dragY-y)
+		c.dragged += math.Hypot(dx, dy)
+		c.pan(dx, dy)
+	}
+	c.dragX, c.dragY = x, y
+}
@@ -0,0 +1,196 @@
+package game
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	evector "github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jtbonhomme/golife/internal/fonts"
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+	colorful "github.com/lucasb-eyer/go-colorful"
+)
+
+func maxCounter(index, rnd10 int) int {
+	return 50 + rnd10 + (25*index+rnd10)%64
+}
+
+func angularToCartesian(dist, orientation float64) (x, y float32) {
+	return float32(dist * math.Cos(orientation)), float32(dist * math.Sin(orientation))
+}
+
+func addVector(position vector.Vector2D, dist, orientation float64) (x, y float32) {
+	acX, acY := angularToCartesian(dist, orientation)
+	return float32(position.X) + acX, float32(position.Y) + acY
+}
+
+// drawCellBody draws the body of a cell centered on a screen location, scaled by the camera zoom.
+func drawCellBody(screen *ebiten.Image, c *cell.Cell, center vector.Vector2D, scale float64, counter int) {
+	var path evector.Path
+	npoints := 16
+
+	indexToDirection := func(i int) float64 {
+		return c.Orientation() - float64(2*i+1)*math.Pi/float64(npoints)
+	}
+	indexToDist := func(i, counter int) float64 {
+		return scale * (c.Size() + c.Size()*0.1*math.Sin(float64(counter)*2*math.Pi/float64(maxCounter(i, int(c.Rnd10())))))
+	}
+
+	for i := 0; i <= npoints; i++ {
+		if i == 0 {
+			path.MoveTo(addVector(center, indexToDist(i, counter), indexToDirection(i)))
+			continue
+		}
+		cpx0, cpy0 := addVector(center, indexToDist(i, counter), indexToDirection(i-1)-math.Pi/16)
+		cpx1, cpy1 := addVector(center, indexToDist(i, counter), indexToDirection(i)+math.Pi/16)
+		cpx2, cpy2 := addVector(center, indexToDist(i, counter), indexToDirection(i))
+		path.CubicTo(cpx0, cpy0, cpx1, cpy1, cpx2, cpy2)
+	}
+
+	cellColor := dietColor(c)
+
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.EvenOdd,
+	}
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(cellColor.R)
+		vs[i].ColorG = float32(cellColor.G)
+		vs[i].ColorB = float32(cellColor.B)
+	}
+	screen.DrawTriangles(vs, is, emptySubImage, op)
+}
+
+// dietHues maps each diet to the hue of the cells having it (120° is green, 0° is red).
+var dietHues = map[cell.Diet]float64{
+	cell.Herbivore: 130,
+	cell.Carnivore: 10,
+	cell.Omnivore:  260,
+}
+
+// dietColor returns the color of a cell body: its hue depends on its diet, bigger cells being darker.
+func dietColor(c *cell.Cell) colorful.Color {
+	lightness := math.Max(0.3, math.Min(0.8, 0.8-c.Size()/100))
+	return colorful.HSLuv(dietHues[c.Diet()], 1, lightness)
+}
+
+// drawEyes draws an eye of a cell centered on a screen location, dist and size being screen lengths.
+func drawEyes(screen *ebiten.Image, rnd *rand.Rand, c *cell.Cell, center vector.Vector2D, dist, side, size, bg float64) {
+	var path evector.Path
+
+	randomizedFloat64 := func(in float64) float64 {
+		return in + rnd.Float64()*2
+	}
+
+	cpx0, cpy0 := addVector(center, dist-randomizedFloat64(size), c.Orientation()+side*math.Pi/randomizedFloat64(12))
+
+	path.Arc(cpx0, cpy0, float32(size), float32(0), float32(2*math.Pi), evector.Clockwise)
+
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.EvenOdd,
+	}
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(bg) / float32(0xff)
+		vs[i].ColorG = float32(bg) / float32(0xff)
+		vs[i].ColorB = float32(bg) / float32(0xff)
+	}
+	screen.DrawTriangles(vs, is, emptySubImage, op)
+}
+
+// drawCell draws a cell body and eyes where the camera displays it, and its state in debug mode.
+func (g *Game) drawCell(screen *ebiten.Image, c *cell.Cell) {
+	if !g.camera.visible(c.Position(), c.Size()*1.1) {
+		return
+	}
+	center, size := g.camera.toScreen(c.Position()), c.Size()*g.camera.zoom
+	drawCellBody(screen, c, center, g.camera.zoom, g.world.Counter())
+	drawEyes(screen, g.rnd, c, center, size*0.9, -1, size*0.1, 0xff)
+	drawEyes(screen, g.rnd, c, center, size*0.9, -1, size*0.05, 0x00)
+	drawEyes(screen, g.rnd, c, center, size*0.9, 1, size*0.1, 0xff)
+	drawEyes(screen, g.rnd, c, center, size*0.9, 1, size*0.05, 0x00)
+	if g.debug {
+		drawVision(screen, c, center, g.camera.zoom)
+		drawBodyBoundaryBox(screen, center, size)
+		msg := c.String()
+		textDim := text.BoundString(fonts.MonoSansRegularFont, msg)
+		textWidth := textDim.Max.X - textDim.Min.X
+		text.Draw(screen,
+			msg,
+			fonts.MonoSansRegularFont,
+			int(center.X)-textWidth/2,
+			int(center.Y+size+5),
+			color.Gray16{0x999f})
+	}
+}
+
+// drawBodyBoundaryBox draws a box around a body centered on a screen location, based on its screen dimension.
+func drawBodyBoundaryBox(screen *ebiten.Image, center vector.Vector2D, size float64) {
+	x, y := center.X, center.Y
+	// Top boundary
+	ebitenutil.DrawLine(
+		screen,
+		x-size,
+		y-size,
+		x+size,
+		y-size,
+		color.Gray16{0xbbbb},
+	)
+	// Right boundary
+	ebitenutil.DrawLine(
+		screen,
+		x+size,
+		y-size,
+		x+size,
+		y+size,
+		color.Gray16{0xbbbb},
+	)
+	// Bottom boundary
+	ebitenutil.DrawLine(
+		screen,
+		x-size,
+		y+size,
+		x+size,
+		y+size,
+		color.Gray16{0xbbbb},
+	)
+	// Left boundary
+	ebitenutil.DrawLine(
+		screen,
+		x-size,
+		y-size,
+		x-size,
+		y+size,
+		color.Gray16{0xbbbb},
+	)
+}
+
+// rayColors maps the kind of target hit by a vision ray to the color of the ray.
+var rayColors = map[cell.RayKind]color.Color{
+	cell.RayNone:    color.Gray16{0xdddd},
+	cell.RayBigger:  color.RGBA{0xff, 0x00, 0x00, 0xff},
+	cell.RaySmaller: color.RGBA{0x00, 0xaa, 0x00, 0xff},
+	cell.RayWall:    color.RGBA{0x00, 0x00, 0xff, 0xff},
+	cell.RayFood:    color.RGBA{0xcc, 0xaa, 0x00, 0xff},
+}
+
+// drawVision draws the rays cast by a cell centered on a screen location, up to their target.
+func drawVision(screen *ebiten.Image, c *cell.Cell, center vector.Vector2D, scale float64) {
+	for _, ray := range c.Vision() {
+		x, y := addVector(center, ray.Distance*scale, ray.Angle)
+		ebitenutil.DrawLine(
+			screen,
+			center.X, center.Y,
+			float64(x), float64(y),
+			rayColors[ray.Kind],
+		)
+	}
+}
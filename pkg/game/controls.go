@@ -0,0 +1,70 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	pauseKey  ebiten.Key = ebiten.KeySpace
+	stepKey   ebiten.Key = ebiten.KeyN
+	fasterKey ebiten.Key = ebiten.KeyEqual
+	slowerKey ebiten.Key = ebiten.KeyMinus
+)
+
+// speeds are the available numbers of world steps per frame,
+// a step being made every few frames below 1.
+var speeds = []float64{0.125, 0.25, 0.5, 1, 2, 4, 8, 16}
+
+// normalSpeed is the index of one step per frame in speeds.
+const normalSpeed = 3
+
+// clock decides how many times the world is stepped at each frame.
+type clock struct {
+	paused  bool
+	step    bool
+	speed   int
+	pending float64
+}
+
+// handleKeys pauses or resumes the simulation, requests a single step, or changes its speed.
+func (c *clock) handleKeys() {
+	if inpututil.IsKeyJustPressed(pauseKey) {
+		c.paused = !c.paused
+		c.pending = 0
+	}
+	if c.paused && inpututil.IsKeyJustPressed(stepKey) {
+		c.step = true
+	}
+	if (inpututil.IsKeyJustPressed(fasterKey) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd)) && c.speed < len(speeds)-1 {
+		c.speed++
+	}
+	if (inpututil.IsKeyJustPressed(slowerKey) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract)) && c.speed > 0 {
+		c.speed--
+	}
+}
+
+// steps returns the number of world steps to make for the current frame.
+func (c *clock) steps() int {
+	if c.paused {
+		if c.step {
+			c.step = false
+			return 1
+		}
+		return 0
+	}
+	c.pending += speeds[c.speed]
+	n := int(c.pending)
+	c.pending -= float64(n)
+	return n
+}
+
+// String returns the simulation mode.
+func (c *clock) String() string {
+	if c.paused {
+		return "paused"
+	}
+	return fmt.Sprintf("running x%g", speeds[c.speed])
+}
@@ -0,0 +1,34 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	evector "github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// drawFood draws the pellets displayed by the camera as green discs.
+func (g *Game) drawFood(screen *ebiten.Image) {
+	var path evector.Path
+	for _, p := range g.world.Food() {
+		if p.IsEaten() || !g.camera.visible(p.Position, p.Size) {
+			continue
+		}
+		center := g.camera.toScreen(p.Position)
+		path.MoveTo(float32(center.X+p.Size*g.camera.zoom), float32(center.Y))
+		path.Arc(float32(center.X), float32(center.Y), float32(p.Size*g.camera.zoom), 0, float32(2*math.Pi), evector.Clockwise)
+	}
+
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.EvenOdd,
+	}
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = 0x33 / float32(0xff)
+		vs[i].ColorG = 0xaa / float32(0xff)
+		vs[i].ColorB = 0x33 / float32(0xff)
+	}
+	screen.DrawTriangles(vs, is, emptySubImage, op)
+}
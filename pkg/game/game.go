@@ -12,11 +12,8 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/jtbonhomme/golife/internal/fonts"
 	"github.com/jtbonhomme/golife/internal/vector"
-	"github.com/jtbonhomme/golife/pkg/cell"
-)
-
-const (
-	nCells int = 50
+	"github.com/jtbonhomme/golife/pkg/sim"
+	log "github.com/sirupsen/logrus"
 )
 
 var (
@@ -26,137 +23,226 @@ var (
 
 func init() {
 	emptyImage.Fill(color.White)
-	rand.Seed(time.Now().UnixNano())
 }
 
+// Game renders a simulation world with ebiten.
 type Game struct {
-	counter       int
-	cells         map[string]*cell.Cell
-	tiles         [][]*Tile
+	world         *sim.World
+	rnd           *rand.Rand
 	TileDimension int
 	ScreenWidth   int
 	ScreenHeight  int
 	debug         bool
 	startTime     time.Time
 	gameDuration  time.Duration
+	snapshotPath  string
+	hud           *hud
+	clock         clock
+	camera        *camera
+	inspector     inspector
+	recorder      *sim.Recorder
+	player        *sim.Player
+	observers     []sim.Observer
+	stepHook      func(*sim.World) error
+}
+
+// New creates a game rendering a new world created from config and seeded with seed.
+func New(config sim.Config, seed int64) *Game {
+	return NewWithWorld(sim.New(config, seed))
 }
 
-func New(w, h, t int) *Game {
+// NewWithWorld creates a game rendering an existing world, e.g. restored from a snapshot.
+func NewWithWorld(world *sim.World) *Game {
 	g := &Game{
-		counter:       0,
-		ScreenWidth:   w,
-		ScreenHeight:  h,
-		TileDimension: t,
+		world:         world,
+		rnd:           rand.New(rand.NewSource(world.Seed())),
+		ScreenWidth:   world.Width,
+		ScreenHeight:  world.Height,
+		TileDimension: world.TileDimension,
 		startTime:     time.Now(),
 		gameDuration:  0,
 		debug:         true,
-		cells:         make(map[string]*cell.Cell),
-	}
-	g.tiles = [][]*Tile{}
-	for i := 0; i < nCells; i++ {
-		c := cell.New(vector.Vector2D{
-			X: float64(rand.Int31n(int32(w))),
-			Y: float64(rand.Int31n(int32(h))),
-		}, g.ScreenWidth, g.ScreenHeight, g.Detect)
-		c.Debug(g.debug)
-		g.cells[c.ID()] = c
-	}
-	g.tiles = make([][]*Tile, g.ScreenWidth/g.TileDimension)
-	for i := 0; i < g.ScreenWidth/g.TileDimension; i++ {
-		g.tiles[i] = make([]*Tile, g.ScreenHeight/g.TileDimension)
-		for j := 0; j < g.ScreenHeight/g.TileDimension; j++ {
-			g.tiles[i][j] = &Tile{x: i, y: j, width: float64(g.TileDimension), height: float64(g.TileDimension), cells: []string{}}
-		}
+		hud:           newHUD(),
+		clock:         clock{speed: normalSpeed},
+		camera:        newCamera(world.Width, world.Height, world.Width, world.Height),
 	}
 	return g
 }
 
-func (g *Game) removeCell(c *cell.Cell) {
-	delete(g.cells, c.ID())
+// SetScreenSize sets the size of the screen, the world being larger or smaller than it.
+func (g *Game) SetScreenSize(width, height int) {
+	g.ScreenWidth, g.ScreenHeight = width, height
+	g.camera.resize(width, height)
 }
 
-func (g *Game) resetTiles() {
-	for i := 0; i < g.ScreenWidth/g.TileDimension; i++ {
-		for j := 0; j < g.ScreenHeight/g.TileDimension; j++ {
-			g.tiles[i][j].ResetCellCount()
-		}
+// SetSnapshotPath sets the file the world is saved to and restored from with the snapshot keys.
+func (g *Game) SetSnapshotPath(path string) {
+	g.snapshotPath = path
+}
+
+// AddObserver registers an observer of the world births and deaths,
+// kept registered when the world is restored from a snapshot.
+func (g *Game) AddObserver(o sim.Observer) {
+	g.observers = append(g.observers, o)
+	g.world.AddObserver(o)
+}
+
+// SetStepHook sets a function called after each world step, e.g. to collect statistics.
+func (g *Game) SetStepHook(hook func(*sim.World) error) {
+	g.stepHook = hook
+}
+
+// setWorld replaces the rendered world, registering the game observers on it.
+func (g *Game) setWorld(world *sim.World) {
+	for _, o := range g.observers {
+		world.AddObserver(o)
 	}
+	g.world = world
+	g.inspector.selectCell(nil)
+	if g.player != nil {
+		log.Warnf("replay abandoned at tick %d", world.Counter())
+		g.player = nil
+	}
+	if g.recorder != nil {
+		log.Warnf("recording restarted at tick %d", world.Counter())
+		g.recorder.Restart(world)
+	}
+	g.TileDimension = world.TileDimension
+	g.camera.worldWidth, g.camera.worldHeight = float64(world.Width), float64(world.Height)
+	g.camera.resize(g.ScreenWidth, g.ScreenHeight)
+}
+
+// World returns the simulation world rendered by the game.
+func (g *Game) World() *sim.World {
+	return g.world
 }
 
 func (g *Game) Update() error {
-	g.counter++
-	g.resetTiles()
-	if len(g.cells) == 0 {
-		return fmt.Errorf("all cells are dead")
+	g.handleSnapshotKeys()
+	g.hud.handleKeys()
+	g.clock.handleKeys()
+	g.camera.handleInput()
+	g.handleUserInputs()
+	for i := g.clock.steps(); i > 0; i-- {
+		if err := g.step(); err != nil {
+			return err
+		}
 	}
+	g.inspector.handleInput(g)
+	g.gameDuration = time.Since(g.startTime).Round(time.Second)
+	return nil
+}
 
-	for _, c := range g.cells {
-		if c.IsDead() {
-			g.removeCell(c)
-			continue
+// step advances the world by one tick.
+func (g *Game) step() error {
+	if g.player != nil {
+		if err := g.player.Apply(g.world); err != nil {
+			return err
 		}
-		// update tile count
-		// x := int(math.Floor(c.Position().X / float64(g.TileDimension)))
-		// y := int(math.Floor(c.Position().Y / float64(g.TileDimension)))
-		// g.tiles[x][y].AddCell(c.ID())
-
-		// update cell state
-		c.Update(g.counter)
 	}
-	g.gameDuration = time.Since(g.startTime).Round(time.Second)
+	if err := g.world.Step(); err != nil {
+		return err
+	}
+	if g.player != nil {
+		g.verify()
+	}
+	if g.recorder != nil {
+		if err := g.recorder.Checkpoint(g.world); err != nil {
+			log.Errorf("can not record checkpoint: %s", err.Error())
+		}
+	}
+	g.hud.record(g.world)
+	g.inspector.record()
+	if g.stepHook != nil {
+		return g.stepHook(g.world)
+	}
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	// blank screen
-	screen.Fill(color.White)
+	// blank world, the screen around it being gray when zoomed out
+	screen.Fill(color.Gray16{0xdddd})
+	origin := g.camera.toScreen(vector.Vector2D{})
+	ebitenutil.DrawRect(
+		screen,
+		origin.X,
+		origin.Y,
+		float64(g.world.Width)*g.camera.zoom,
+		float64(g.world.Height)*g.camera.zoom,
+		color.White,
+	)
 	// draw first debug information
 	if g.debug {
-		// for i := 0; i < g.ScreenWidth/g.TileDimension; i++ {
-		// 	for j := 0; j < g.ScreenHeight/g.TileDimension; j++ {
-		// 		if g.tiles[i][j].CellCount() > 0 {
-		// 			ebitenutil.DrawRect(
-		// 				screen,
-		// 				float64(i*g.TileDimension),
-		// 				float64(j*g.TileDimension),
-		// 				float64(g.TileDimension),
-		// 				float64(g.TileDimension),
-		// 				color.Gray16{0xeeee},
-		// 			)
-		// 		}
-		// 	}
-		// }
+		tileSize := float64(g.TileDimension) * g.camera.zoom
+		for i, column := range g.world.Tiles() {
+			for j, tile := range column {
+				if tile.CellCount() > 0 {
+					corner := g.camera.toScreen(vector.Vector2D{X: float64(i * g.TileDimension), Y: float64(j * g.TileDimension)})
+					ebitenutil.DrawRect(
+						screen,
+						corner.X,
+						corner.Y,
+						tileSize,
+						tileSize,
+						color.Gray16{0xeeee},
+					)
+				}
+			}
+		}
 
 		ebitenutil.DebugPrint(
 			screen,
-			fmt.Sprintf("TPS: %0.2f\nFPS: %0.2f\nCounter: %d\nCreatures: %d",
+			fmt.Sprintf("TPS: %0.2f\nFPS: %0.2f\nCounter: %d\nCreatures: %d\nFood: %d",
 				ebiten.CurrentTPS(),
 				ebiten.CurrentFPS(),
-				g.counter,
-				len(g.cells),
+				g.world.Counter(),
+				len(g.world.Cells()),
+				len(g.world.Food()),
 			),
 		)
 		g.linkCells(screen, 250.0)
 	}
 	// Draw elements on top of debug information
-	for _, c := range g.cells {
+	g.drawFood(screen)
+	for _, c := range g.world.Cells() {
 		if !c.IsDead() {
-			c.Draw(screen, g.counter, emptySubImage)
+			g.drawCell(screen, c)
 		}
 	}
+	g.drawHighlight(screen)
 	g.drawTimeElapsed(screen)
+	g.hud.draw(screen, g.world, g.mode())
+	g.drawPanel(screen)
+}
+
+// mode describes the clock state, and whether the run is recorded or replayed.
+func (g *Game) mode() string {
+	mode := g.clock.String()
+	if g.recorder != nil {
+		mode += ", recording"
+	}
+	if g.player != nil {
+		mode += ", replaying"
+	}
+	return mode
 }
 
 // linkCells draws a line between two close agents
 func (g *Game) linkCells(screen *ebiten.Image, radius float64) {
-	for _, ci := range g.cells {
-		for _, cj := range g.cells {
-			if ci.ID() != cj.ID() && ci.Position().Distance(cj.Position()) < ci.DetectionRadius() && !cj.IsDead() {
-				// Draw line between agents
+	for _, ci := range g.world.Cells() {
+		if !g.camera.visible(ci.Position(), ci.DetectionRadius()) {
+			continue
+		}
+		for _, cj := range g.world.Detect(ci.Position(), ci.DetectionRadius()) {
+			if ci != cj {
+				// Draw line between agents, across the world edges if they are closer this way
+				target := ci.Position()
+				target.Add(ci.Position().TorusDisplacement(cj.Position(), float64(g.world.Width), float64(g.world.Height)))
+				from, to := g.camera.toScreen(ci.Position()), g.camera.toScreen(target)
 				ebitenutil.DrawLine(
 					screen,
-					ci.Position().X, ci.Position().Y,
-					cj.Position().X, cj.Position().Y,
+					from.X, from.Y,
+					to.X, to.Y,
 					color.Gray16{0xcccc},
 				)
 			}
@@ -165,8 +251,11 @@ func (g *Game) linkCells(screen *ebiten.Image, radius float64) {
 }
 
 // Layout takes the outside size (e.g., the window size) and returns the (logical) screen size.
-// If you don't have to adjust the screen size with the outside size, just return a fixed size.
+// The screen follows the window size, the camera showing more or less of the world.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	if outsideWidth != g.ScreenWidth || outsideHeight != g.ScreenHeight {
+		g.SetScreenSize(outsideWidth, outsideHeight)
+	}
 	return g.ScreenWidth, g.ScreenHeight
 }
 
@@ -184,16 +273,3 @@ func (g *Game) drawTimeElapsed(screen *ebiten.Image) {
 		color.Black,
 	)
 }
-
-// Detect returns all cells located in a radius from (x,y)
-func (g *Game) Detect(pos vector.Vector2D, radius float64) []*cell.Cell {
-	nearestCells := []*cell.Cell{}
-
-	for _, c := range g.cells {
-		if pos.SquareDistance(c.Position()) < radius*radius {
-			nearestCells = append(nearestCells, c)
-		}
-	}
-
-	return nearestCells
-}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jtbonhomme/golife/pkg/sim"
+	"github.com/jtbonhomme/golife/pkg/stats"
+)
+
+const (
+	hudKey ebiten.Key = ebiten.KeyH
+	// hudHistory is the number of ticks shown by the charts, one pixel per tick.
+	hudHistory     = 240
+	hudChartHeight = 40
+	hudMargin      = 10
+	hudPadding     = 4
+	hudLabelHeight = 16
+	// hudHeaderLines is the number of text lines giving the world state above the charts.
+	hudHeaderLines = 2
+	hudBins        = 24
+)
+
+var (
+	hudBackground = color.RGBA{0xf8, 0xf8, 0xf8, 0xdd}
+	hudAxis       = color.Gray16{0xbbbb}
+)
+
+// hudSeries is a scrolling history of values drawn as a line chart.
+type hudSeries struct {
+	label  string
+	color  color.Color
+	values []float64
+}
+
+func (s *hudSeries) add(v float64) {
+	s.values = append(s.values, v)
+	if len(s.values) > hudHistory {
+		s.values = s.values[len(s.values)-hudHistory:]
+	}
+}
+
+// hud is a panel giving the world tick, population and clock mode, and charting the population over time.
+type hud struct {
+	visible bool
+	series  []*hudSeries
+	sizes   []float64
+}
+
+func newHUD() *hud {
+	return &hud{
+		series: []*hudSeries{
+			{label: "population", color: color.RGBA{0x33, 0x33, 0xcc, 0xff}},
+			{label: "avg size", color: color.RGBA{0xcc, 0x33, 0x33, 0xff}},
+			{label: "avg energy", color: color.RGBA{0x00, 0x99, 0x33, 0xff}},
+		},
+	}
+}
+
+// handleKeys toggles the panel.
+func (h *hud) handleKeys() {
+	if inpututil.IsKeyJustPressed(hudKey) {
+		h.visible = !h.visible
+	}
+}
+
+// record adds the current state of the world to the charts.
+// The history is recorded even when the panel is hidden, to be complete when it shows up.
+func (h *hud) record(w *sim.World) {
+	s := stats.Measure(w.Counter(), w.Cells())
+	h.series[0].add(float64(s.Population))
+	h.series[1].add(s.Size.Mean)
+	h.series[2].add(s.Energy.Mean)
+	h.sizes = h.sizes[:0]
+	for _, c := range w.Cells() {
+		if !c.IsDead() {
+			h.sizes = append(h.sizes, c.Size())
+		}
+	}
+}
+
+// draw draws the panel in the bottom right corner of the screen. Its header, always drawn,
+// gives the tick, the population and the clock mode, and the charts below it are drawn when the panel is visible.
+func (h *hud) draw(screen *ebiten.Image, w *sim.World, mode string) {
+	width := float64(hudHistory + 2*hudPadding)
+	height := float64(hudHeaderLines)*hudLabelHeight + 2*hudPadding
+	if h.visible {
+		height += float64(len(h.series)+1) * (hudChartHeight + hudLabelHeight)
+	}
+	bounds := screen.Bounds()
+	x := float64(bounds.Dx()) - width - hudMargin
+	y := float64(bounds.Dy()) - height - hudMargin
+	ebitenutil.DrawRect(screen, x, y, width, height, hudBackground)
+
+	x += hudPadding
+	y += hudPadding
+	ebitenutil.DebugPrintAt(
+		screen,
+		fmt.Sprintf("tick: %d  population: %d\nmode: %s", w.Counter(), len(w.Cells()), mode),
+		int(x),
+		int(y),
+	)
+	if !h.visible {
+		return
+	}
+	y += hudHeaderLines * hudLabelHeight
+	for _, s := range h.series {
+		drawLineChart(screen, s, x, y)
+		y += hudChartHeight + hudLabelHeight
+	}
+	drawHistogram(screen, h.sizes, x, y)
+}
+
+// drawLineChart draws a series scaled to its maximum, its label and last value above it.
+func drawLineChart(screen *ebiten.Image, s *hudSeries, x, y float64) {
+	last, max := 0.0, 0.0
+	for _, v := range s.values {
+		max = math.Max(max, v)
+	}
+	if len(s.values) > 0 {
+		last = s.values[len(s.values)-1]
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s: %0.1f (max %0.1f)", s.label, last, max), int(x), int(y))
+
+	bottom := y + hudLabelHeight + hudChartHeight
+	ebitenutil.DrawLine(screen, x, bottom, x+hudHistory, bottom, hudAxis)
+	if max == 0 {
+		return
+	}
+	scale := hudChartHeight / max
+	for i := 1; i < len(s.values); i++ {
+		ebitenutil.DrawLine(
+			screen,
+			x+float64(i-1), bottom-s.values[i-1]*scale,
+			x+float64(i), bottom-s.values[i]*scale,
+			s.color,
+		)
+	}
+}
+
+// drawHistogram draws the distribution of cell sizes, from 0 to the biggest size.
+func drawHistogram(screen *ebiten.Image, sizes []float64, x, y float64) {
+	max := 0.0
+	for _, size := range sizes {
+		max = math.Max(max, size)
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("size histogram (0 - %0.1f)", max), int(x), int(y))
+
+	bottom := y + hudLabelHeight + hudChartHeight
+	ebitenutil.DrawLine(screen, x, bottom, x+hudHistory, bottom, hudAxis)
+	if max == 0 {
+		return
+	}
+	bins := make([]int, hudBins)
+	highest := 0
+	for _, size := range sizes {
+		i := int(size / max * hudBins)
+		if i >= hudBins {
+			i = hudBins - 1
+		}
+		bins[i]++
+		if bins[i] > highest {
+			highest = bins[i]
+		}
+	}
+	binWidth := float64(hudHistory) / hudBins
+	for i, n := range bins {
+		h := float64(n) / float64(highest) * hudChartHeight
+		ebitenutil.DrawRect(screen, x+float64(i)*binWidth+1, bottom-h, binWidth-2, h, color.RGBA{0x99, 0x66, 0xcc, 0xff})
+	}
+}
@@ -0,0 +1,227 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+const (
+	followKey   ebiten.Key = ebiten.KeyF
+	deselectKey ebiten.Key = ebiten.KeyEscape
+	// clickTolerance is the distance the cursor may move between a press and a release for a click to select a cell.
+	clickTolerance = 4.0
+	// energyHistory is the number of ticks shown by the energy sparkline, one pixel per tick.
+	energyHistory = 160
+
+	panelX          = 10
+	panelY          = 110
+	panelWidth      = energyHistory + 2*panelPadding
+	panelPadding    = 6
+	panelLineHeight = 16
+	sparklineHeight = 30
+	barsHeight      = 24
+)
+
+var (
+	highlightColor = color.RGBA{0xff, 0x88, 0x00, 0xff}
+	positiveColor  = color.RGBA{0x00, 0x99, 0x33, 0xff}
+	negativeColor  = color.RGBA{0xcc, 0x33, 0x33, 0xff}
+)
+
+// inspector shows the detailed state of a cell selected with a click.
+type inspector struct {
+	selected *cell.Cell
+	follow   bool
+	energy   []float64
+}
+
+// handleInput selects the cell under the cursor on click, and toggles the camera following it.
+func (i *inspector) handleInput(g *Game) {
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && g.camera.dragged < clickTolerance {
+		x, y := ebiten.CursorPosition()
+		i.selectCell(g.cellAt(g.camera.toWorld(float64(x), float64(y))))
+	}
+	if inpututil.IsKeyJustPressed(deselectKey) {
+		i.selectCell(nil)
+	}
+	if inpututil.IsKeyJustPressed(followKey) {
+		i.follow = !i.follow
+	}
+	if i.follow && i.selected != nil && !i.selected.IsDead() {
+		g.camera.center(i.selected.Position())
+	}
+}
+
+// selectCell selects a cell, or clears the selection if c is nil.
+func (i *inspector) selectCell(c *cell.Cell) {
+	i.selected = c
+	i.energy = i.energy[:0]
+	if c != nil {
+		i.energy = append(i.energy, c.Energy())
+	}
+}
+
+// record adds the energy of the selected cell to its history.
+func (i *inspector) record() {
+	if i.selected == nil || i.selected.IsDead() {
+		return
+	}
+	i.energy = append(i.energy, i.selected.Energy())
+	if len(i.energy) > energyHistory {
+		i.energy = i.energy[len(i.energy)-energyHistory:]
+	}
+}
+
+// cellAt returns the living cell whose body covers a world location, nil if there is none.
+func (g *Game) cellAt(p vector.Vector2D) *cell.Cell {
+	width, height := float64(g.world.Width), float64(g.world.Height)
+	for _, c := range g.world.Cells() {
+		if !c.IsDead() && c.Position().TorusDistance(p, width, height) <= c.Size() {
+			return c
+		}
+	}
+	return nil
+}
+
+// drawHighlight draws a ring around the selected cell.
+func (g *Game) drawHighlight(screen *ebiten.Image) {
+	c := g.inspector.selected
+	if c == nil || c.IsDead() {
+		return
+	}
+	center := g.camera.toScreen(c.Position())
+	segments := 32
+	for _, radius := range []float64{c.Size()*g.camera.zoom + 4, c.Size()*g.camera.zoom + 5} {
+		for k := 0; k < segments; k++ {
+			a0 := float64(k) * 2 * math.Pi / float64(segments)
+			a1 := float64(k+1) * 2 * math.Pi / float64(segments)
+			ebitenutil.DrawLine(
+				screen,
+				center.X+radius*math.Cos(a0), center.Y+radius*math.Sin(a0),
+				center.X+radius*math.Cos(a1), center.Y+radius*math.Sin(a1),
+				highlightColor,
+			)
+		}
+	}
+}
+
+// drawPanel draws the state of the selected cell on the left side of the screen.
+func (g *Game) drawPanel(screen *ebiten.Image) {
+	c := g.inspector.selected
+	if c == nil {
+		return
+	}
+	genome := c.Genome()
+	status := "alive"
+	if c.IsDead() {
+		status = "dead (" + string(c.DeathCause()) + ")"
+	}
+	follow := "off"
+	if g.inspector.follow {
+		follow = "on"
+	}
+	parent := "none"
+	if c.Parent() != "" {
+		parent = cell.ShortID(c.Parent())
+	}
+	brainLayers := "none"
+	if genome.Network != nil {
+		brainLayers = fmt.Sprintf("neat, %d nodes", len(genome.Network.Nodes))
+	} else if genome.Brain.Enabled() {
+		brainLayers = fmt.Sprint(genome.Brain.Layers)
+	}
+	lines := []string{
+		"cell " + cell.ShortID(c.ID()),
+		"lineage " + cell.ShortID(c.Lineage()),
+		"parent " + parent,
+		"diet: " + string(c.Diet()),
+		"status: " + status,
+		fmt.Sprintf("age: %d (born at %d)", c.Age(g.world.Counter()), c.Birth()),
+		fmt.Sprintf("kills: %d", c.Kills()),
+		fmt.Sprintf("size: %0.1f energy: %0.1f", c.Size(), c.Energy()),
+		fmt.Sprintf("detection radius: %0.1f", genome.DetectionRadius),
+		fmt.Sprintf("velocity factor: %0.2f", genome.VelocityFactor),
+		fmt.Sprintf("growth interval: %d", genome.GrowthInterval),
+		fmt.Sprintf("eat ratio: %0.2f", genome.EatRatio),
+		"brain: " + brainLayers,
+		"follow (F): " + follow,
+	}
+	sensors := c.Sensors()
+	if sensors == nil {
+		sensors = c.VisionInputs()
+	}
+	layers := append([][]float64{sensors}, c.Activations()...)
+
+	height := float64(len(lines)*panelLineHeight+panelLineHeight+sparklineHeight+len(layers)*(panelLineHeight+barsHeight)) + 2*panelPadding
+	ebitenutil.DrawRect(screen, panelX, panelY, panelWidth, height, hudBackground)
+	x, y := float64(panelX+panelPadding), float64(panelY+panelPadding)
+	ebitenutil.DebugPrintAt(screen, strings.Join(lines, "\n"), int(x), int(y))
+	y += float64(len(lines) * panelLineHeight)
+
+	ebitenutil.DebugPrintAt(screen, "energy", int(x), int(y))
+	y += panelLineHeight
+	drawSparkline(screen, g.inspector.energy, c.Energy(), x, y)
+	y += sparklineHeight
+
+	for l, values := range layers {
+		label := fmt.Sprintf("layer %d", l)
+		switch l {
+		case 0:
+			label = "sensors"
+		case len(layers) - 1:
+			label = "motors"
+		}
+		ebitenutil.DebugPrintAt(screen, label, int(x), int(y))
+		y += panelLineHeight
+		drawBars(screen, values, x, y)
+		y += barsHeight
+	}
+}
+
+// drawSparkline draws a history of values scaled to the maximum of the history and max.
+func drawSparkline(screen *ebiten.Image, values []float64, max, x, y float64) {
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	bottom := y + sparklineHeight - 2
+	ebitenutil.DrawLine(screen, x, bottom, x+energyHistory, bottom, hudAxis)
+	if max <= 0 {
+		return
+	}
+	scale := (sparklineHeight - 4) / max
+	for k := 1; k < len(values); k++ {
+		ebitenutil.DrawLine(
+			screen,
+			x+float64(k-1), bottom-values[k-1]*scale,
+			x+float64(k), bottom-values[k]*scale,
+			positiveColor,
+		)
+	}
+}
+
+// drawBars draws values clamped to [-1, 1] as bars above or below a middle line.
+func drawBars(screen *ebiten.Image, values []float64, x, y float64) {
+	middle := y + barsHeight/2 - 2
+	ebitenutil.DrawLine(screen, x, middle, x+energyHistory, middle, hudAxis)
+	if len(values) == 0 {
+		return
+	}
+	width := math.Min(float64(energyHistory)/float64(len(values)), 12)
+	for k, v := range values {
+		v = math.Max(-1, math.Min(1, v))
+		h := math.Abs(v) * (barsHeight/2 - 2)
+		top, clr := middle-h, color.Color(positiveColor)
+		if v < 0 {
+			top, clr = middle, negativeColor
+		}
+		ebitenutil.DrawRect(screen, x+float64(k)*width, top, math.Max(width-1, 1), h, clr)
+	}
+}
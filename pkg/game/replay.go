@@ -0,0 +1,85 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jtbonhomme/golife/pkg/sim"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	spawnButton ebiten.MouseButton = ebiten.MouseButtonRight
+	lessFoodKey ebiten.Key         = ebiten.KeyBracketLeft
+	moreFoodKey ebiten.Key         = ebiten.KeyBracketRight
+	despawnKey  ebiten.Key         = ebiten.KeyDelete
+	// foodRateFactor is the factor applied to the food growth rate by the food keys.
+	foodRateFactor = 1.5
+)
+
+// SetRecorder records the run in a replay, including the inputs of the user.
+func (g *Game) SetRecorder(r *sim.Recorder) {
+	g.recorder = r
+}
+
+// SetPlayer drives the world from a replay, verifying its checkpoints. The user can not alter a replayed run.
+func (g *Game) SetPlayer(p *sim.Player) {
+	g.player = p
+}
+
+// handleUserInputs spawns a cell under the cursor, despawns the selected cell, or changes the food growth rate.
+func (g *Game) handleUserInputs() {
+	if g.player != nil {
+		return
+	}
+	if inpututil.IsMouseButtonJustPressed(spawnButton) {
+		x, y := ebiten.CursorPosition()
+		pos := g.camera.toWorld(float64(x), float64(y))
+		if pos.X >= 0 && pos.Y >= 0 && pos.X < float64(g.world.Width) && pos.Y < float64(g.world.Height) {
+			g.apply(sim.Input{Tick: g.world.Counter(), Kind: sim.SpawnInput, Position: &pos})
+		}
+	}
+	if c := g.inspector.selected; inpututil.IsKeyJustPressed(despawnKey) && c != nil && !c.IsDead() {
+		g.apply(sim.Input{Tick: g.world.Counter(), Kind: sim.DespawnInput, Cell: c.ID()})
+	}
+	for _, k := range []struct {
+		key    ebiten.Key
+		factor float64
+	}{{lessFoodKey, 1 / foodRateFactor}, {moreFoodKey, foodRateFactor}} {
+		if inpututil.IsKeyJustPressed(k.key) {
+			config := g.world.Config()
+			config.Food.Rate *= k.factor
+			g.apply(sim.Input{Tick: g.world.Counter(), Kind: sim.ConfigInput, Config: &config})
+			log.Infof("food rate set to %0.3f", config.Food.Rate)
+		}
+	}
+}
+
+// apply applies an input of the user to the world, and records it.
+func (g *Game) apply(in sim.Input) {
+	if err := g.world.Apply(in); err != nil {
+		log.Errorf("can not apply %s input: %s", in.Kind, err.Error())
+		return
+	}
+	if g.recorder != nil {
+		g.recorder.Record(in)
+	}
+}
+
+// verify verifies the world state against the replay checkpoint of the current tick, if any.
+// The game is paused and the replay abandoned once the run diverges from it.
+func (g *Game) verify() {
+	ok, err := g.player.Verify(g.world)
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		g.clock.paused = true
+		g.player = nil
+		return
+	}
+	if ok {
+		log.Infof("replay checkpoint %d verified at tick %d", g.player.Checkpoints(), g.world.Counter())
+	}
+	if g.player.Done() {
+		log.Infof("end of replay at tick %d, the run goes on freely", g.world.Counter())
+		g.player = nil
+	}
+}
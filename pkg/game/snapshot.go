@@ -0,0 +1,41 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jtbonhomme/golife/pkg/sim"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	saveKey    ebiten.Key = ebiten.KeyF5
+	restoreKey ebiten.Key = ebiten.KeyF9
+)
+
+// handleSnapshotKeys saves the world to the snapshot file, or restores it from this file.
+func (g *Game) handleSnapshotKeys() {
+	if g.snapshotPath == "" {
+		return
+	}
+	if inpututil.IsKeyJustPressed(saveKey) {
+		if err := g.world.Snapshot().Save(g.snapshotPath); err != nil {
+			log.Errorf("can not save snapshot: %s", err.Error())
+			return
+		}
+		log.Infof("world saved to %s at tick %d", g.snapshotPath, g.world.Counter())
+	}
+	if inpututil.IsKeyJustPressed(restoreKey) {
+		s, err := sim.LoadSnapshot(g.snapshotPath)
+		if err != nil {
+			log.Errorf("can not load snapshot: %s", err.Error())
+			return
+		}
+		world, err := sim.Restore(s)
+		if err != nil {
+			log.Errorf("can not restore snapshot: %s", err.Error())
+			return
+		}
+		g.setWorld(world)
+		log.Infof("world restored from %s at tick %d", g.snapshotPath, g.world.Counter())
+	}
+}
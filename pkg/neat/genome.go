@@ -0,0 +1,164 @@
+// Package neat implements NeuroEvolution of Augmenting Topologies: neural networks whose topology
+// grows by mutation, from a minimal network connecting inputs to outputs.
+package neat
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+// NodeKind is the role of a node in a network.
+type NodeKind string
+
+const (
+	Input  NodeKind = "input"
+	Bias   NodeKind = "bias"
+	Hidden NodeKind = "hidden"
+	Output NodeKind = "output"
+)
+
+// Node is a neuron of a network.
+type Node struct {
+	ID   int      `json:"id"`
+	Kind NodeKind `json:"kind"`
+}
+
+// Connection is a weighted link from a node to another one.
+type Connection struct {
+	// Innovation identifies the structural mutation which created the connection,
+	// shared by all connections linking the same nodes.
+	Innovation int     `json:"innovation"`
+	In         int     `json:"in"`
+	Out        int     `json:"out"`
+	Weight     float64 `json:"weight"`
+	Enabled    bool    `json:"enabled"`
+}
+
+// Genome encodes a network. Nodes are sorted by ID, and connections by innovation.
+type Genome struct {
+	// Hidden and Output are the activations of hidden and output nodes.
+	Hidden      brain.Activation `json:"hidden"`
+	Output      brain.Activation `json:"output"`
+	Nodes       []Node           `json:"nodes"`
+	Connections []Connection     `json:"connections"`
+}
+
+// Minimal returns a genome connecting each input and a bias to each output, with weights drawn
+// uniformly in [-1, 1] from rnd. Inputs have IDs from 0, followed by the bias and the outputs, and the
+// connection from the i-th source to the j-th output has the innovation i*outputs+j, as registered by
+// NewInnovations.
+func Minimal(rnd *rand.Rand, inputs, outputs int, hidden, output brain.Activation) *Genome {
+	g := &Genome{Hidden: hidden, Output: output}
+	for i := 0; i < inputs; i++ {
+		g.Nodes = append(g.Nodes, Node{ID: i, Kind: Input})
+	}
+	g.Nodes = append(g.Nodes, Node{ID: inputs, Kind: Bias})
+	for j := 0; j < outputs; j++ {
+		g.Nodes = append(g.Nodes, Node{ID: inputs + 1 + j, Kind: Output})
+	}
+	for i := 0; i <= inputs; i++ {
+		for j := 0; j < outputs; j++ {
+			g.Connections = append(g.Connections, Connection{
+				Innovation: i*outputs + j,
+				In:         i,
+				Out:        inputs + 1 + j,
+				Weight:     rnd.Float64()*2 - 1,
+				Enabled:    true,
+			})
+		}
+	}
+	return g
+}
+
+// Copy returns a deep copy of the genome.
+func (g *Genome) Copy() *Genome {
+	c := &Genome{
+		Hidden:      g.Hidden,
+		Output:      g.Output,
+		Nodes:       make([]Node, len(g.Nodes)),
+		Connections: make([]Connection, len(g.Connections)),
+	}
+	copy(c.Nodes, g.Nodes)
+	copy(c.Connections, g.Connections)
+	return c
+}
+
+// node returns the node of a given ID, and false if there is none.
+func (g *Genome) node(id int) (Node, bool) {
+	i := sort.Search(len(g.Nodes), func(i int) bool { return g.Nodes[i].ID >= id })
+	if i < len(g.Nodes) && g.Nodes[i].ID == id {
+		return g.Nodes[i], true
+	}
+	return Node{}, false
+}
+
+// addNode inserts a node, keeping nodes sorted by ID.
+func (g *Genome) addNode(n Node) {
+	g.Nodes = append(g.Nodes, n)
+	sort.SliceStable(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+}
+
+// addConnection inserts a connection, keeping connections sorted by innovation.
+func (g *Genome) addConnection(c Connection) {
+	g.Connections = append(g.Connections, c)
+	sort.SliceStable(g.Connections, func(i, j int) bool { return g.Connections[i].Innovation < g.Connections[j].Innovation })
+}
+
+// connected returns true if a connection links two nodes, enabled or not.
+func (g *Genome) connected(in, out int) bool {
+	for _, c := range g.Connections {
+		if c.In == in && c.Out == out {
+			return true
+		}
+	}
+	return false
+}
+
+// reaches returns true if a path of connections, enabled or not, leads from a node to another one.
+func (g *Genome) reaches(from, to int) bool {
+	visited := map[int]bool{}
+	stack := []int{from}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == to {
+			return true
+		}
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+		for _, c := range g.Connections {
+			if c.In == n {
+				stack = append(stack, c.Out)
+			}
+		}
+	}
+	return false
+}
+
+// Crossover returns a genome mixing two parents, fitter being the fittest one. Connections sharing
+// an innovation come from either parent with an equal probability, the others from the fitter parent.
+// A connection disabled in a parent is disabled in the child with a probability of 0.75.
+func Crossover(rnd *rand.Rand, fitter, other *Genome) *Genome {
+	child := fitter.Copy()
+	genes := map[int]Connection{}
+	for _, c := range other.Connections {
+		genes[c.Innovation] = c
+	}
+	for i, c := range child.Connections {
+		o, ok := genes[c.Innovation]
+		if !ok {
+			continue
+		}
+		if rnd.Float64() < 0.5 {
+			child.Connections[i].Weight = o.Weight
+		}
+		if !c.Enabled || !o.Enabled {
+			child.Connections[i].Enabled = rnd.Float64() >= 0.75
+		}
+	}
+	return child
+}
@@ -0,0 +1,110 @@
+package neat
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+// crossedGenome returns a genome of an input, the bias, an output and two hidden nodes 3 and 4, whose
+// connection from 3 to 4 was disabled by a crossover, leaving no enabled path from 3 to 4.
+func crossedGenome() *Genome {
+	return &Genome{
+		Hidden: brain.ReLU,
+		Output: brain.Linear,
+		Nodes: []Node{
+			{ID: 0, Kind: Input}, {ID: 1, Kind: Bias}, {ID: 2, Kind: Output}, {ID: 3, Kind: Hidden}, {ID: 4, Kind: Hidden},
+		},
+		Connections: []Connection{
+			{Innovation: 0, In: 0, Out: 2, Weight: 1, Enabled: true},
+			{Innovation: 1, In: 1, Out: 2, Weight: 1, Enabled: true},
+			{Innovation: 2, In: 0, Out: 3, Weight: 1, Enabled: true},
+			{Innovation: 3, In: 3, Out: 2, Weight: 1, Enabled: true},
+			{Innovation: 4, In: 0, Out: 4, Weight: 1, Enabled: true},
+			{Innovation: 5, In: 4, Out: 2, Weight: 1, Enabled: true},
+			{Innovation: 6, In: 3, Out: 4, Weight: 1, Enabled: false},
+		},
+	}
+}
+
+func TestReaches(t *testing.T) {
+	g := crossedGenome()
+	for _, tc := range []struct {
+		from, to int
+		reaches  bool
+	}{
+		{0, 2, true},
+		{0, 4, true},
+		{3, 4, true},
+		{4, 3, false},
+		{2, 0, false},
+	} {
+		if g.reaches(tc.from, tc.to) != tc.reaches {
+			t.Errorf("%d reaches %d: %v, expected %v", tc.from, tc.to, !tc.reaches, tc.reaches)
+		}
+	}
+}
+
+func TestCrossoverNoCycle(t *testing.T) {
+	config := Config{AddConnectionRate: 1, AddNodeRate: 0.5}
+	for seed := int64(0); seed < 200; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		g := crossedGenome().Mutate(rnd, 0, 0, config, NewInnovations(1, 1))
+		// a crossover with a parent where it is enabled may enable any connection back
+		other := g.Copy()
+		for i := range other.Connections {
+			other.Connections[i].Enabled = true
+		}
+		for i := 0; i < 10; i++ {
+			if _, err := NewNetwork(Crossover(rnd, g, other)); err != nil {
+				t.Fatalf("seed %d: %s", seed, err)
+			}
+		}
+	}
+}
+
+func TestCrossover(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inn := NewInnovations(3, 2)
+	config := Config{AddConnectionRate: 0.5, AddNodeRate: 0.5}
+	fitter := Minimal(rnd, 3, 2, brain.Tanh, brain.Tanh)
+	other := Minimal(rnd, 3, 2, brain.Tanh, brain.Tanh)
+	for i := 0; i < 5; i++ {
+		fitter = fitter.Mutate(rnd, 0.5, 0.5, config, inn)
+		other = other.Mutate(rnd, 0.5, 0.5, config, inn)
+	}
+	weights := map[int][2]float64{}
+	for _, c := range fitter.Connections {
+		weights[c.Innovation] = [2]float64{c.Weight, c.Weight}
+	}
+	for _, c := range other.Connections {
+		if w, ok := weights[c.Innovation]; ok {
+			weights[c.Innovation] = [2]float64{w[0], c.Weight}
+		}
+	}
+
+	child := Crossover(rnd, fitter, other)
+	if len(child.Nodes) != len(fitter.Nodes) || len(child.Connections) != len(fitter.Connections) {
+		t.Fatalf("child of %d nodes and %d connections, expected the topology of the fitter parent",
+			len(child.Nodes), len(child.Connections))
+	}
+	for i, c := range child.Connections {
+		f := fitter.Connections[i]
+		if c.Innovation != f.Innovation || c.In != f.In || c.Out != f.Out {
+			t.Fatalf("child connection %+v, expected %+v", c, f)
+		}
+		if w := weights[c.Innovation]; c.Weight != w[0] && c.Weight != w[1] {
+			t.Errorf("connection %d weighs %v, expected one of %v", c.Innovation, c.Weight, w)
+		}
+	}
+	if _, err := NewNetwork(child); err != nil {
+		t.Fatal(err)
+	}
+
+	// the child does not share its connections with the fitter parent
+	child.Connections[0].Weight++
+	if child.Connections[0].Weight == fitter.Connections[0].Weight {
+		t.Error("child connections are shared with the fitter parent")
+	}
+}
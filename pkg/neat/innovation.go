@@ -0,0 +1,75 @@
+package neat
+
+import "fmt"
+
+// Innovations registers the structural mutations of a population, for the same mutation to be given
+// the same innovation number, or the same node ID, in all genomes.
+type Innovations struct {
+	NextNode       int `json:"nextNode"`
+	NextInnovation int `json:"nextInnovation"`
+	// Connections maps "in>out" node pairs to the innovation of the connection linking them.
+	Connections map[string]int `json:"connections"`
+	// Splits maps the innovation of a connection to the ID of the node added on it.
+	Splits map[int]int `json:"splits"`
+}
+
+// NewInnovations returns a registry knowing the connections of minimal genomes with given numbers of
+// inputs and outputs.
+func NewInnovations(inputs, outputs int) *Innovations {
+	inn := &Innovations{
+		NextNode:    inputs + 1 + outputs,
+		Connections: map[string]int{},
+		Splits:      map[int]int{},
+	}
+	for i := 0; i <= inputs; i++ {
+		for j := 0; j < outputs; j++ {
+			inn.Connections[connectionKey(i, inputs+1+j)] = inn.NextInnovation
+			inn.NextInnovation++
+		}
+	}
+	return inn
+}
+
+func connectionKey(in, out int) string {
+	return fmt.Sprintf("%d>%d", in, out)
+}
+
+// connection returns the innovation of a connection linking two nodes, registering it if it is new.
+func (inn *Innovations) connection(in, out int) int {
+	key := connectionKey(in, out)
+	if i, ok := inn.Connections[key]; ok {
+		return i
+	}
+	i := inn.NextInnovation
+	inn.NextInnovation++
+	inn.Connections[key] = i
+	return i
+}
+
+// split returns the ID of the node added on a connection, registering it if it is new.
+func (inn *Innovations) split(innovation int) int {
+	if id, ok := inn.Splits[innovation]; ok {
+		return id
+	}
+	id := inn.NextNode
+	inn.NextNode++
+	inn.Splits[innovation] = id
+	return id
+}
+
+// Copy returns a deep copy of the registry.
+func (inn *Innovations) Copy() *Innovations {
+	c := &Innovations{
+		NextNode:       inn.NextNode,
+		NextInnovation: inn.NextInnovation,
+		Connections:    make(map[string]int, len(inn.Connections)),
+		Splits:         make(map[int]int, len(inn.Splits)),
+	}
+	for k, v := range inn.Connections {
+		c.Connections[k] = v
+	}
+	for k, v := range inn.Splits {
+		c.Splits[k] = v
+	}
+	return c
+}
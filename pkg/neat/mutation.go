@@ -0,0 +1,118 @@
+package neat
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// addConnectionAttempts is the number of node pairs drawn to find two nodes which can be connected.
+const addConnectionAttempts int = 20
+
+// Config holds the parameters of topology mutations and speciation.
+type Config struct {
+	// AddNodeRate is the probability for a genome to gain a node splitting one of its connections.
+	AddNodeRate float64 `json:"addNodeRate"`
+	// AddConnectionRate is the probability for a genome to gain a connection between two of its nodes.
+	AddConnectionRate float64 `json:"addConnectionRate"`
+	// ExcessCoefficient, DisjointCoefficient and WeightCoefficient weigh excess connections,
+	// disjoint connections and the mean weight difference of matching connections in the
+	// compatibility distance between two genomes.
+	ExcessCoefficient   float64 `json:"excessCoefficient"`
+	DisjointCoefficient float64 `json:"disjointCoefficient"`
+	WeightCoefficient   float64 `json:"weightCoefficient"`
+	// CompatibilityThreshold is the distance under which two genomes belong to the same species.
+	CompatibilityThreshold float64 `json:"compatibilityThreshold"`
+}
+
+// DefaultConfig returns the default NEAT parameters.
+func DefaultConfig() Config {
+	return Config{
+		AddNodeRate:            0.03,
+		AddConnectionRate:      0.05,
+		ExcessCoefficient:      1,
+		DisjointCoefficient:    1,
+		WeightCoefficient:      0.4,
+		CompatibilityThreshold: 3,
+	}
+}
+
+// Validate checks the parameters are consistent.
+func (c Config) Validate() error {
+	for name, rate := range map[string]float64{"addNodeRate": c.AddNodeRate, "addConnectionRate": c.AddConnectionRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s (%v) must be in [0, 1]", name, rate)
+		}
+	}
+	if c.CompatibilityThreshold <= 0 {
+		return fmt.Errorf("compatibilityThreshold must be positive, got %v", c.CompatibilityThreshold)
+	}
+	return nil
+}
+
+// Mutate returns a mutated copy of the genome. Each weight mutates with a probability of rate, following
+// a gaussian distribution of standard deviation scale, then a node and a connection may be added as
+// configured, their innovations being registered in inn.
+func (g *Genome) Mutate(rnd *rand.Rand, rate, scale float64, config Config, inn *Innovations) *Genome {
+	m := g.Copy()
+	for i := range m.Connections {
+		if rnd.Float64() < rate {
+			m.Connections[i].Weight += rnd.NormFloat64() * scale
+		}
+	}
+	if rnd.Float64() < config.AddConnectionRate {
+		m.mutateAddConnection(rnd, inn)
+	}
+	if rnd.Float64() < config.AddNodeRate {
+		m.mutateAddNode(rnd, inn)
+	}
+	return m
+}
+
+// mutateAddConnection links two unconnected nodes with a random weight. Connections never lead to an
+// input or the bias, nor make a cycle, even through disabled connections a crossover may enable back,
+// for the network to stay feed-forward.
+func (g *Genome) mutateAddConnection(rnd *rand.Rand, inn *Innovations) {
+	for i := 0; i < addConnectionAttempts; i++ {
+		in := g.Nodes[rnd.Intn(len(g.Nodes))]
+		out := g.Nodes[rnd.Intn(len(g.Nodes))]
+		if in.Kind == Output || out.Kind == Input || out.Kind == Bias || in.ID == out.ID {
+			continue
+		}
+		if g.connected(in.ID, out.ID) || g.reaches(out.ID, in.ID) {
+			continue
+		}
+		g.addConnection(Connection{
+			Innovation: inn.connection(in.ID, out.ID),
+			In:         in.ID,
+			Out:        out.ID,
+			Weight:     rnd.Float64()*2 - 1,
+			Enabled:    true,
+		})
+		return
+	}
+}
+
+// mutateAddNode disables a random enabled connection and replaces it with a hidden node, linked to the
+// connection input with a weight of 1 and to its output with the connection weight.
+func (g *Genome) mutateAddNode(rnd *rand.Rand, inn *Innovations) {
+	enabled := []int{}
+	for i, c := range g.Connections {
+		if c.Enabled {
+			enabled = append(enabled, i)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+	i := enabled[rnd.Intn(len(enabled))]
+	split := g.Connections[i]
+	id := inn.split(split.Innovation)
+	// the connection was already split once, before being enabled back by a crossover
+	if _, ok := g.node(id); ok {
+		return
+	}
+	g.Connections[i].Enabled = false
+	g.addNode(Node{ID: id, Kind: Hidden})
+	g.addConnection(Connection{Innovation: inn.connection(split.In, id), In: split.In, Out: id, Weight: 1, Enabled: true})
+	g.addConnection(Connection{Innovation: inn.connection(id, split.Out), In: id, Out: split.Out, Weight: split.Weight, Enabled: true})
+}
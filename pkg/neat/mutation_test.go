@@ -0,0 +1,91 @@
+package neat
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+func TestMutateWeights(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	g := Minimal(rnd, 3, 2, brain.Tanh, brain.Tanh)
+	inn := NewInnovations(3, 2)
+
+	same := g.Mutate(rnd, 0, 1, Config{}, inn)
+	for i, c := range same.Connections {
+		if c != g.Connections[i] {
+			t.Errorf("connection %+v mutated to %+v with a rate of 0", g.Connections[i], c)
+		}
+	}
+
+	mutated := g.Mutate(rnd, 1, 1, Config{}, inn)
+	if len(mutated.Nodes) != len(g.Nodes) || len(mutated.Connections) != len(g.Connections) {
+		t.Fatal("topology mutated without any structural mutation rate")
+	}
+	for i, c := range mutated.Connections {
+		if c.Weight == g.Connections[i].Weight {
+			t.Errorf("connection %d did not mutate with a rate of 1", c.Innovation)
+		}
+	}
+}
+
+func TestMutateAddNode(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	inn := NewInnovations(2, 1)
+	g := Minimal(rnd, 2, 1, brain.Tanh, brain.Tanh)
+	m := g.Mutate(rnd, 0, 0, Config{AddNodeRate: 1}, inn)
+	if len(m.Nodes) != len(g.Nodes)+1 || len(m.Connections) != len(g.Connections)+2 {
+		t.Fatalf("%d nodes and %d connections, expected a node and two connections more", len(m.Nodes), len(m.Connections))
+	}
+	disabled := 0
+	for _, c := range m.Connections {
+		if !c.Enabled {
+			disabled++
+		}
+	}
+	if disabled != 1 {
+		t.Errorf("%d connections disabled, expected the split one", disabled)
+	}
+	if _, err := NewNetwork(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same split in another genome gets the same node and innovations
+	for {
+		o := Minimal(rnd, 2, 1, brain.Tanh, brain.Tanh).Mutate(rnd, 0, 0, Config{AddNodeRate: 1}, inn)
+		if o.Nodes[len(o.Nodes)-1].ID != m.Nodes[len(m.Nodes)-1].ID {
+			continue
+		}
+		for i, c := range o.Connections {
+			if c.Innovation != m.Connections[i].Innovation || c.In != m.Connections[i].In || c.Out != m.Connections[i].Out {
+				t.Errorf("connection %+v, expected the innovation of %+v", c, m.Connections[i])
+			}
+		}
+		break
+	}
+}
+
+func TestMutateAddConnection(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	inn := NewInnovations(2, 2)
+	g := Minimal(rnd, 2, 2, brain.Tanh, brain.Tanh)
+	for i := 0; i < 20; i++ {
+		g = g.Mutate(rnd, 0.1, 0.1, Config{AddNodeRate: 0.5, AddConnectionRate: 1}, inn)
+	}
+	seen := map[int]bool{}
+	for _, c := range g.Connections {
+		in, _ := g.node(c.In)
+		out, _ := g.node(c.Out)
+		if in.Kind == Output || out.Kind == Input || out.Kind == Bias {
+			t.Errorf("connection %+v from a %s to a %s", c, in.Kind, out.Kind)
+		}
+		if seen[c.Innovation] {
+			t.Errorf("innovation %d is used twice", c.Innovation)
+		}
+		seen[c.Innovation] = true
+	}
+	if _, err := NewNetwork(g); err != nil {
+		t.Fatal(err)
+	}
+}
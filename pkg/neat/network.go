@@ -0,0 +1,144 @@
+package neat
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+// Network is the feed-forward neural network encoded by a genome.
+type Network struct {
+	genome  *Genome
+	inputs  []int
+	bias    int
+	hidden  []int
+	outputs []int
+	// order lists the hidden and output nodes, each one coming after the nodes feeding it.
+	order    []int
+	output   map[int]bool
+	incoming map[int][]Connection
+	values   map[int]float64
+}
+
+// NewNetwork builds the network encoded by a genome.
+func NewNetwork(g *Genome) (*Network, error) {
+	for _, a := range []brain.Activation{g.Hidden, g.Output} {
+		if _, err := brain.ParseActivation(string(a)); err != nil {
+			return nil, err
+		}
+	}
+	n := &Network{
+		genome:   g,
+		bias:     -1,
+		incoming: map[int][]Connection{},
+		output:   map[int]bool{},
+		values:   map[int]float64{},
+	}
+	pending := map[int]int{}
+	for _, node := range g.Nodes {
+		switch node.Kind {
+		case Input:
+			n.inputs = append(n.inputs, node.ID)
+		case Bias:
+			n.bias = node.ID
+		case Hidden:
+			n.hidden = append(n.hidden, node.ID)
+		case Output:
+			n.outputs = append(n.outputs, node.ID)
+			n.output[node.ID] = true
+		default:
+			return nil, fmt.Errorf("node %d has an unknown kind %q", node.ID, node.Kind)
+		}
+	}
+	outgoing := map[int][]int{}
+	for _, c := range g.Connections {
+		if !c.Enabled {
+			continue
+		}
+		if _, ok := g.node(c.In); !ok {
+			return nil, fmt.Errorf("connection %d comes from an unknown node %d", c.Innovation, c.In)
+		}
+		if _, ok := g.node(c.Out); !ok {
+			return nil, fmt.Errorf("connection %d leads to an unknown node %d", c.Innovation, c.Out)
+		}
+		n.incoming[c.Out] = append(n.incoming[c.Out], c)
+		outgoing[c.In] = append(outgoing[c.In], c.Out)
+		pending[c.Out]++
+	}
+
+	// topological sort, from the nodes without any incoming connection
+	ready := []int{}
+	for _, node := range g.Nodes {
+		if pending[node.ID] == 0 {
+			ready = append(ready, node.ID)
+		}
+	}
+	sorted := 0
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		sorted++
+		if node, _ := g.node(id); node.Kind == Hidden || node.Kind == Output {
+			n.order = append(n.order, id)
+		}
+		for _, out := range outgoing[id] {
+			pending[out]--
+			if pending[out] == 0 {
+				ready = append(ready, out)
+			}
+		}
+	}
+	if sorted != len(g.Nodes) {
+		return nil, fmt.Errorf("connections make a cycle")
+	}
+	return n, nil
+}
+
+// Genome returns the genome encoding the network.
+func (n *Network) Genome() *Genome {
+	return n.genome
+}
+
+// Activate feeds the inputs forward through the network and returns its outputs, ordered by node ID.
+// It fails if the number of inputs does not match the input nodes of the genome.
+func (n *Network) Activate(inputs []float64) ([]float64, error) {
+	if len(inputs) != len(n.inputs) {
+		return nil, fmt.Errorf("network expects %d inputs, got %d", len(n.inputs), len(inputs))
+	}
+	for i, id := range n.inputs {
+		n.values[id] = inputs[i]
+	}
+	if n.bias >= 0 {
+		n.values[n.bias] = 1
+	}
+	for _, id := range n.order {
+		sum := 0.0
+		for _, c := range n.incoming[id] {
+			sum += c.Weight * n.values[c.In]
+		}
+		if n.output[id] {
+			n.values[id] = n.genome.Output.Apply(sum)
+		} else {
+			n.values[id] = n.genome.Hidden.Apply(sum)
+		}
+	}
+	return n.read(n.outputs), nil
+}
+
+// read returns the values of some nodes computed by the last activation.
+func (n *Network) read(ids []int) []float64 {
+	values := make([]float64, len(ids))
+	for i, id := range ids {
+		values[i] = n.values[id]
+	}
+	return values
+}
+
+// Activations returns the values of the hidden nodes, ordered by node ID, then of the outputs, computed by
+// the last activation.
+func (n *Network) Activations() [][]float64 {
+	if len(n.hidden) == 0 {
+		return [][]float64{n.read(n.outputs)}
+	}
+	return [][]float64{n.read(n.hidden), n.read(n.outputs)}
+}
@@ -0,0 +1,58 @@
+package neat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+func TestActivate(t *testing.T) {
+	// inputs 0 and 1, bias 2, output 3, and hidden 4 splitting the connection from 0 to 3
+	g := &Genome{
+		Hidden: brain.ReLU,
+		Output: brain.Linear,
+		Nodes:  []Node{{ID: 0, Kind: Input}, {ID: 1, Kind: Input}, {ID: 2, Kind: Bias}, {ID: 3, Kind: Output}, {ID: 4, Kind: Hidden}},
+		Connections: []Connection{
+			{Innovation: 0, In: 0, Out: 3, Weight: 5, Enabled: false},
+			{Innovation: 1, In: 1, Out: 3, Weight: 2, Enabled: true},
+			{Innovation: 2, In: 2, Out: 3, Weight: 0.5, Enabled: true},
+			{Innovation: 3, In: 0, Out: 4, Weight: -1, Enabled: true},
+			{Innovation: 4, In: 4, Out: 3, Weight: 3, Enabled: true},
+			{Innovation: 5, In: 2, Out: 4, Weight: 1, Enabled: true},
+		},
+	}
+	n, err := NewNetwork(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		inputs []float64
+		output float64
+	}{
+		// hidden = relu(-x0 + 1), output = 2*x1 + 0.5 + 3*hidden
+		{[]float64{0, 0}, 3.5},
+		{[]float64{0.5, 1}, 4},
+		{[]float64{2, -1}, -1.5},
+	} {
+		outputs, err := n.Activate(tc.inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(outputs) != 1 || math.Abs(outputs[0]-tc.output) > 1e-9 {
+			t.Errorf("inputs %v give %v, expected %v", tc.inputs, outputs, tc.output)
+		}
+	}
+	if _, err := n.Activate([]float64{1}); err == nil {
+		t.Error("network activated with a wrong number of inputs")
+	}
+}
+
+func TestNewNetworkCycle(t *testing.T) {
+	g := crossedGenome()
+	g.Connections = append(g.Connections, Connection{Innovation: 7, In: 4, Out: 3, Weight: 1, Enabled: true})
+	g.Connections[6].Enabled = true
+	if _, err := NewNetwork(g); err == nil {
+		t.Error("network built from connections making a cycle")
+	}
+}
@@ -0,0 +1,78 @@
+package neat
+
+import "math"
+
+// smallGenome is the number of connections under which compatibility distances are not normalized
+// by the genome size.
+const smallGenome int = 20
+
+// Distance returns the compatibility distance between two genomes, growing with the number of excess
+// and disjoint connections, normalized by the size of the largest genome, and with the mean weight
+// difference of matching connections.
+func Distance(a, b *Genome, config Config) float64 {
+	var excess, disjoint, matching int
+	weights := 0.0
+	i, j := 0, 0
+	for i < len(a.Connections) && j < len(b.Connections) {
+		ca, cb := a.Connections[i], b.Connections[j]
+		switch {
+		case ca.Innovation == cb.Innovation:
+			matching++
+			weights += math.Abs(ca.Weight - cb.Weight)
+			i++
+			j++
+		case ca.Innovation < cb.Innovation:
+			disjoint++
+			i++
+		default:
+			disjoint++
+			j++
+		}
+	}
+	excess = len(a.Connections) - i + len(b.Connections) - j
+
+	n := math.Max(float64(len(a.Connections)), float64(len(b.Connections)))
+	if n < float64(smallGenome) {
+		n = 1
+	}
+	distance := (config.ExcessCoefficient*float64(excess) + config.DisjointCoefficient*float64(disjoint)) / n
+	if matching > 0 {
+		distance += config.WeightCoefficient * weights / float64(matching)
+	}
+	return distance
+}
+
+// Speciate groups genomes in species and returns the species index of each genome. A genome joins the
+// first species whose first member is closer than the compatibility threshold, or founds a new one.
+func Speciate(genomes []*Genome, config Config) []int {
+	species := make([]int, len(genomes))
+	representatives := []*Genome{}
+	for i, g := range genomes {
+		species[i] = -1
+		for s, r := range representatives {
+			if Distance(g, r, config) < config.CompatibilityThreshold {
+				species[i] = s
+				break
+			}
+		}
+		if species[i] < 0 {
+			species[i] = len(representatives)
+			representatives = append(representatives, g)
+		}
+	}
+	return species
+}
+
+// ShareFitness returns the fitness of each genome divided by the size of its species, for large species
+// not to take over the population.
+func ShareFitness(fitness []float64, species []int) []float64 {
+	sizes := map[int]int{}
+	for _, s := range species {
+		sizes[s]++
+	}
+	shared := make([]float64, len(fitness))
+	for i, f := range fitness {
+		shared[i] = f / float64(sizes[species[i]])
+	}
+	return shared
+}
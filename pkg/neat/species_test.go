@@ -0,0 +1,49 @@
+package neat
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+)
+
+func TestDistance(t *testing.T) {
+	config := DefaultConfig()
+	rnd := rand.New(rand.NewSource(5))
+	a := Minimal(rnd, 2, 1, brain.Tanh, brain.Tanh)
+	if d := Distance(a, a.Copy(), config); d != 0 {
+		t.Errorf("distance of a genome to its copy is %v", d)
+	}
+
+	b := a.Copy()
+	b.Connections[0].Weight += 1
+	if d := Distance(a, b, config); math.Abs(d-config.WeightCoefficient/3) > 1e-9 {
+		t.Errorf("distance %v, expected the mean weight difference %v", d, config.WeightCoefficient/3)
+	}
+
+	// a split disables a matching connection and adds two excess ones
+	c := a.Mutate(rnd, 0, 0, Config{AddNodeRate: 1}, NewInnovations(2, 1))
+	expected := 2 * config.ExcessCoefficient
+	if d := Distance(a, c, config); math.Abs(d-expected) > 1e-9 {
+		t.Errorf("distance %v, expected %v", d, expected)
+	}
+}
+
+func TestSpeciate(t *testing.T) {
+	config := DefaultConfig()
+	config.CompatibilityThreshold = 0.5
+	rnd := rand.New(rand.NewSource(6))
+	a := Minimal(rnd, 2, 1, brain.Tanh, brain.Tanh)
+	b := a.Mutate(rnd, 0, 0, Config{AddNodeRate: 1}, NewInnovations(2, 1))
+	genomes := []*Genome{a, b, a.Copy(), b.Copy(), b}
+	species := Speciate(genomes, config)
+	if !reflect.DeepEqual(species, []int{0, 1, 0, 1, 1}) {
+		t.Fatalf("species %v, expected [0 1 0 1 1]", species)
+	}
+	shared := ShareFitness([]float64{4, 6, 2, 3, 9}, species)
+	if !reflect.DeepEqual(shared, []float64{2, 2, 1, 1, 3}) {
+		t.Errorf("shared fitness %v, expected [2 2 1 1 3]", shared)
+	}
+}
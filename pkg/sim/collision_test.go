@@ -0,0 +1,33 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+func TestResolveCollisions(t *testing.T) {
+	config := testConfig()
+	config.Population = 0
+	config.Collisions = true
+	// cells of the same size cannot eat each other
+	config.Cell.MinSize = 10
+	config.Cell.MaxSize = 10
+	w := New(config, 42)
+	a := w.SpawnAt(vector.Vector2D{X: 100, Y: 100})
+	b := w.SpawnAt(vector.Vector2D{X: 105, Y: 100})
+	c := w.SpawnAt(vector.Vector2D{X: 400, Y: 300})
+	// tiles indexed before the cells moved next to each other
+	w.resetTiles()
+
+	w.resolveCollisions()
+	if a.Intersect(b) {
+		t.Errorf("cells at %v and %v still overlap", a.Position(), b.Position())
+	}
+	if a.Position().X >= 100 || b.Position().X <= 105 {
+		t.Errorf("cells at %v and %v were not pushed apart along their axis", a.Position(), b.Position())
+	}
+	if !c.Position().IsEqual(vector.Vector2D{X: 400, Y: 300}) {
+		t.Errorf("lone cell moved to %v", c.Position())
+	}
+}
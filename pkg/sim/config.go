@@ -0,0 +1,103 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/food"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the parameters of a simulation.
+type Config struct {
+	// Width and Height are the world dimensions.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// TileDimension is the size of the spatial index tiles.
+	TileDimension int `json:"tileDimension"`
+	// Population is the number of cells at the world creation.
+	Population int `json:"population"`
+	// Spawner generates cells without any parent.
+	Spawner Spawner `json:"spawner"`
+	// Food describes the pellets growing in the world.
+	Food food.Config `json:"food"`
+	// Collisions enables the physical resolution of collisions between cells.
+	Collisions bool `json:"collisions"`
+	// Cell holds the parameters of cells life.
+	Cell cell.Config `json:"cell"`
+}
+
+// DefaultConfig returns the default simulation parameters.
+func DefaultConfig() Config {
+	return Config{
+		Width:         1280,
+		Height:        720,
+		TileDimension: 80,
+		Population:    50,
+		Spawner:       DefaultSpawner(),
+		Food:          food.DefaultConfig(),
+		Collisions:    false,
+		Cell:          cell.DefaultConfig(),
+	}
+}
+
+// LoadConfig reads a configuration file, as YAML if it ends with .yaml or .yml, as TOML if it ends with .toml,
+// and as JSON if it ends with .json. Keys are the same in all formats. Missing parameters keep their default value.
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	// YAML and TOML documents are converted to JSON, for all formats to share the JSON keys and checks
+	var doc map[string]interface{}
+	switch filepath.Ext(path) {
+	case ".json":
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &doc)
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	default:
+		return config, fmt.Errorf("unsupported config file %s, expected a .json, .yaml, .yml or .toml extension", path)
+	}
+	if err == nil && doc != nil {
+		b, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return config, fmt.Errorf("can not parse config %s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return config, fmt.Errorf("can not parse config %s: %w", path, err)
+	}
+	return config, config.Validate()
+}
+
+// Validate checks the parameters are consistent.
+func (c Config) Validate() error {
+	if c.Width <= 0 || c.Height <= 0 {
+		return fmt.Errorf("world dimensions must be positive, got %dx%d", c.Width, c.Height)
+	}
+	if c.TileDimension <= 0 {
+		return fmt.Errorf("tileDimension must be positive, got %d", c.TileDimension)
+	}
+	if c.Population < 0 {
+		return fmt.Errorf("population must not be negative, got %d", c.Population)
+	}
+	if c.Spawner.Rate < 0 || c.Spawner.Rate > 1 {
+		return fmt.Errorf("spawner.rate (%v) must be in [0, 1]", c.Spawner.Rate)
+	}
+	if err := c.Food.Validate(); err != nil {
+		return fmt.Errorf("invalid food config: %w", err)
+	}
+	if err := c.Cell.Validate(); err != nil {
+		return fmt.Errorf("invalid cell config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package sim
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigByExtension(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+	}{
+		{"golife.json", `{"width": 640, "cell": {"maxSize": 40}}`},
+		{"golife.yaml", "width: 640\ncell:\n  maxSize: 40\n"},
+		{"golife.yml", "width: 640\ncell:\n  maxSize: 40\n"},
+		{"golife.toml", "width = 640\n[cell]\nmaxSize = 40\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := LoadConfig(writeConfig(t, tc.name, tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != 640 || config.Cell.MaxSize != 40 {
+				t.Errorf("width %d and max size %v, expected 640 and 40", config.Width, config.Cell.MaxSize)
+			}
+			// missing parameters keep their default value
+			if config.Height != DefaultConfig().Height || config.Cell.MinSize != DefaultConfig().Cell.MinSize {
+				t.Errorf("height %d and min size %v, expected the defaults", config.Height, config.Cell.MinSize)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"golife.ini", "width = 640\n", "unsupported config file"},
+		{"golife", `{"width": 640}`, "unsupported config file"},
+		{"golife.json", `{"widht": 640}`, `unknown field "widht"`},
+		{"golife.yaml", "cell:\n  maxSise: 40\n", `unknown field "maxSise"`},
+		{"golife.toml", "widht = 640\n", `unknown field "widht"`},
+		{"golife.yaml", "width: [640\n", "can not parse config"},
+		{"golife.json", `{"width": -1}`, "world dimensions must be positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tc.name, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("error %v, expected %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package sim
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	log "github.com/sirupsen/logrus"
+)
+
+// EventKind is the kind of a life event of a cell.
+type EventKind string
+
+const (
+	// BirthEvent is a cell joining the world.
+	BirthEvent EventKind = "birth"
+	// DeathEvent is a cell leaving the world.
+	DeathEvent EventKind = "death"
+	// MealEvent is a cell eating another one or a pellet.
+	MealEvent EventKind = "meal"
+)
+
+// Event is a life event of a cell.
+type Event struct {
+	Tick int       `json:"tick"`
+	Kind EventKind `json:"event"`
+	Cell string    `json:"cell"`
+	// Parent and Diet describe a born cell.
+	Parent string    `json:"parent,omitempty"`
+	Diet   cell.Diet `json:"diet,omitempty"`
+	// Cause, Predator and Age describe a dead cell.
+	Cause    cell.DeathCause `json:"cause,omitempty"`
+	Predator string          `json:"predator,omitempty"`
+	Age      int             `json:"age,omitempty"`
+	// Prey, Food and Energy describe a meal.
+	Prey   string  `json:"prey,omitempty"`
+	Food   bool    `json:"food,omitempty"`
+	Energy float64 `json:"energy,omitempty"`
+}
+
+func birthEvent(w *World, c *cell.Cell) Event {
+	return Event{Tick: w.counter, Kind: BirthEvent, Cell: c.ID(), Parent: c.Parent(), Diet: c.Diet()}
+}
+
+func deathEvent(c *cell.Cell) Event {
+	return Event{
+		Tick:     c.Death(),
+		Kind:     DeathEvent,
+		Cell:     c.ID(),
+		Cause:    c.DeathCause(),
+		Predator: c.Predator(),
+		Age:      c.Age(c.Death()),
+	}
+}
+
+func mealEvent(w *World, c *cell.Cell, m cell.Meal) Event {
+	return Event{Tick: w.counter, Kind: MealEvent, Cell: c.ID(), Prey: m.Prey, Food: m.Prey == "", Energy: m.Energy}
+}
+
+// logEvent emits an event as a structured debug log.
+func logEvent(e Event) {
+	if !log.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+	fields := log.Fields{"tick": e.Tick, "cell": e.Cell}
+	switch e.Kind {
+	case BirthEvent:
+		fields["parent"] = e.Parent
+		fields["diet"] = e.Diet
+	case DeathEvent:
+		fields["cause"] = e.Cause
+		fields["age"] = e.Age
+		if e.Predator != "" {
+			fields["predator"] = e.Predator
+		}
+	case MealEvent:
+		fields["food"] = e.Food
+		fields["energy"] = e.Energy
+		if e.Prey != "" {
+			fields["prey"] = e.Prey
+		}
+	}
+	log.WithFields(fields).Debugf("cell %s", e.Kind)
+}
+
+// MealObserver is an observer also notified of the meals of cells.
+type MealObserver interface {
+	Observer
+	// Ate is called when a cell ate another one or a pellet during its update.
+	Ate(w *World, c *cell.Cell, m cell.Meal)
+}
+
+// notifyMeals notifies the meals of a cell during its last update.
+func (w *World) notifyMeals(c *cell.Cell) {
+	for _, m := range c.Meals() {
+		logEvent(mealEvent(w, c, m))
+		for _, o := range w.observers {
+			if mo, ok := o.(MealObserver); ok {
+				mo.Ate(w, c, m)
+			}
+		}
+	}
+}
+
+// EventLog writes the births, deaths and meals of a world as JSON lines.
+type EventLog struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewEventLog creates an event log writing to wr.
+func NewEventLog(wr io.Writer) *EventLog {
+	return &EventLog{enc: json.NewEncoder(wr)}
+}
+
+func (l *EventLog) write(e Event) {
+	if l.err == nil {
+		l.err = l.enc.Encode(e)
+	}
+}
+
+// Born writes a birth event.
+func (l *EventLog) Born(w *World, c *cell.Cell) {
+	l.write(birthEvent(w, c))
+}
+
+// Died writes a death event.
+func (l *EventLog) Died(w *World, c *cell.Cell) {
+	l.write(deathEvent(c))
+}
+
+// Ate writes a meal event.
+func (l *EventLog) Ate(w *World, c *cell.Cell, m cell.Meal) {
+	l.write(mealEvent(w, c, m))
+}
+
+// Err returns the first error met while writing events, if any.
+func (l *EventLog) Err() error {
+	return l.err
+}
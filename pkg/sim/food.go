@@ -0,0 +1,49 @@
+package sim
+
+import (
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/food"
+)
+
+// growFood adds the pellets growing during a tick.
+func (w *World) growFood() {
+	w.pellets = append(w.pellets, w.config.Food.Grow(w.rnd, w.Width, w.Height, len(w.pellets))...)
+}
+
+// removeEatenFood removes eaten pellets, preserving the order of the remaining ones.
+func (w *World) removeEatenFood() {
+	left := w.pellets[:0]
+	for _, p := range w.pellets {
+		if !p.IsEaten() {
+			left = append(left, p)
+		}
+	}
+	for i := len(left); i < len(w.pellets); i++ {
+		w.pellets[i] = nil
+	}
+	w.pellets = left
+}
+
+// Food returns the pellets lying in the world.
+func (w *World) Food() []*food.Pellet {
+	return w.pellets
+}
+
+// DetectFood returns all pellets not eaten yet located in a radius from (x,y), the world being a torus.
+// Only the tiles overlapping the radius are scanned.
+func (w *World) DetectFood(pos vector.Vector2D, radius float64) []*food.Pellet {
+	nearestPellets := []*food.Pellet{}
+	width, height := float64(w.Width), float64(w.Height)
+
+	for _, i := range w.tileSpan(pos.X, radius, width, len(w.tiles)) {
+		for _, j := range w.tileSpan(pos.Y, radius, height, len(w.tiles[i])) {
+			for _, p := range w.tiles[i][j].Food() {
+				if !p.IsEaten() && pos.TorusSquareDistance(p.Position, width, height) < radius*radius {
+					nearestPellets = append(nearestPellets, p)
+				}
+			}
+		}
+	}
+
+	return nearestPellets
+}
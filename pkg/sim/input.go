@@ -0,0 +1,107 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// InputKind is the kind of an external input changing the course of a simulation.
+type InputKind string
+
+const (
+	// SpawnInput adds a cell at a given position.
+	SpawnInput InputKind = "spawn"
+	// ConfigInput changes the simulation parameters.
+	ConfigInput InputKind = "config"
+	// DespawnInput removes a cell from the world.
+	DespawnInput InputKind = "despawn"
+)
+
+// Input is an external input applied to a world between two ticks.
+type Input struct {
+	// Tick is the world counter when the input is applied, before the next step.
+	Tick     int              `json:"tick"`
+	Kind     InputKind        `json:"kind"`
+	Position *vector.Vector2D `json:"position,omitempty"`
+	Config   *Config          `json:"config,omitempty"`
+	// Cell is the ID of the cell targeted by the input.
+	Cell string `json:"cell,omitempty"`
+}
+
+// Apply applies an external input to the world.
+func (w *World) Apply(in Input) error {
+	switch in.Kind {
+	case SpawnInput:
+		if in.Position == nil {
+			return fmt.Errorf("spawn input without position")
+		}
+		w.SpawnAt(*in.Position)
+	case ConfigInput:
+		if in.Config == nil {
+			return fmt.Errorf("config input without config")
+		}
+		return w.SetConfig(*in.Config)
+	case DespawnInput:
+		return w.Despawn(in.Cell)
+	default:
+		return fmt.Errorf("unknown input kind %q", in.Kind)
+	}
+	return nil
+}
+
+// SpawnAt adds a new random cell at a given position.
+func (w *World) SpawnAt(pos vector.Vector2D) *cell.Cell {
+	return w.add(cell.New(w.rnd, &w.config.Cell, pos, w.counter, w.Width, w.Height, w))
+}
+
+// SpawnGenome adds a new cell carrying a given genome at a given position.
+func (w *World) SpawnGenome(pos vector.Vector2D, genome cell.Genome) *cell.Cell {
+	return w.add(cell.NewWithGenome(w.rnd, &w.config.Cell, pos, genome, w.counter, w.Width, w.Height, w))
+}
+
+// add adds a new cell without any parent to the world.
+func (w *World) add(c *cell.Cell) *cell.Cell {
+	w.cells = append(w.cells, c)
+	w.notifyBirth(c)
+	// the cell is visible to the others from the next step
+	x, y := w.tileIndex(c.Position())
+	w.tiles[x][y].AddCell(c)
+	return c
+}
+
+// Despawn removes a living cell from the world at the next step.
+func (w *World) Despawn(id string) error {
+	for _, c := range w.cells {
+		if c.ID() == id && !c.IsDead() {
+			c.Kill(w.counter, cell.Despawn)
+			return nil
+		}
+	}
+	return fmt.Errorf("no living cell %s", id)
+}
+
+// SetConfig changes the simulation parameters. The world dimensions can not be changed, nor the cells
+// sensors and brain, which the networks of living cells are built for. A new maximum velocity applies
+// to living cells too.
+func (w *World) SetConfig(config Config) error {
+	if config.Width != w.config.Width || config.Height != w.config.Height || config.TileDimension != w.config.TileDimension {
+		return fmt.Errorf("world dimensions can not be changed")
+	}
+	if !config.Cell.SameBrain(&w.config.Cell) {
+		return fmt.Errorf("cells vision rays and brain can not be changed")
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	velocityChanged := config.Cell.MaxVelocity != w.config.Cell.MaxVelocity
+	// cells keep referencing the cell parameters of the world
+	w.config = config
+	if velocityChanged {
+		for _, c := range w.cells {
+			c.ResetMaxVelocity()
+		}
+	}
+	return nil
+}
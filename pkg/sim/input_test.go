@@ -0,0 +1,73 @@
+package sim
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/brain"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+func TestSetConfigRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(c *Config)
+	}{
+		{"width", func(c *Config) { c.Width *= 2 }},
+		{"tile dimension", func(c *Config) { c.TileDimension /= 2 }},
+		{"vision rays", func(c *Config) { c.Cell.VisionRays++ }},
+		{"hidden layers", func(c *Config) { c.Cell.Brain.Hidden = []int{6, 4} }},
+		{"brain activation", func(c *Config) { c.Cell.Brain.Activation = brain.ReLU }},
+		{"neat", func(c *Config) {
+			neatConfig := neat.DefaultConfig()
+			c.Cell.Brain.NEAT = &neatConfig
+		}},
+		{"invalid", func(c *Config) { c.Food.Rate = -1 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := New(testConfig(), 3)
+			config := w.Config()
+			tc.change(&config)
+			if err := w.SetConfig(config); err == nil {
+				t.Fatal("parameters change accepted")
+			}
+			if err := w.Apply(Input{Kind: ConfigInput, Config: &config}); err == nil {
+				t.Fatal("parameters change input accepted")
+			}
+			// a rejected change leaves the previous parameters
+			if !reflect.DeepEqual(w.Config(), testConfig()) {
+				t.Error("rejected parameters applied")
+			}
+		})
+	}
+}
+
+func TestSetConfigAccepted(t *testing.T) {
+	w := New(testConfig(), 3)
+	config := w.Config()
+	config.Food.Rate *= 2
+	if err := w.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if w.Config().Food.Rate != config.Food.Rate {
+		t.Errorf("food rate is %g, expected %g", w.Config().Food.Rate, config.Food.Rate)
+	}
+	step(t, w, 10)
+}
+
+func TestSetConfigMaxVelocity(t *testing.T) {
+	w := New(testConfig(), 3)
+	step(t, w, 50)
+	config := w.Config()
+	config.Cell.MaxVelocity /= 10
+	if err := w.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	step(t, w, 1)
+	for _, c := range w.Cells() {
+		if v := c.Velocity(); v.MagnitudeSquared() > config.Cell.MaxVelocity*config.Cell.MaxVelocity+1e-12 {
+			t.Errorf("cell %s moves at %v, faster than %v", c.ID(), math.Sqrt(v.MagnitudeSquared()), config.Cell.MaxVelocity)
+		}
+	}
+}
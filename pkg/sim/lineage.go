@@ -0,0 +1,238 @@
+package sim
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// LineageRecord is the life of a cell in the world ancestry.
+type LineageRecord struct {
+	ID string `json:"id"`
+	// Parent is the ID of the cell this one was born from, empty without any parent.
+	Parent string `json:"parent,omitempty"`
+	Birth  int    `json:"birth"`
+	// Death and Cause are the tick the cell died at and the reason why, zero while it is alive.
+	Death int             `json:"death,omitempty"`
+	Cause cell.DeathCause `json:"cause,omitempty"`
+	// Predator is the ID of the cell which ate this one, if any.
+	Predator string `json:"predator,omitempty"`
+}
+
+// Alive returns true if the cell of the record has not died yet.
+func (r LineageRecord) Alive() bool {
+	return r.Cause == ""
+}
+
+// lifespan returns the number of ticks the cell lived, up to a given tick if it is alive.
+func (r LineageRecord) lifespan(counter int) int {
+	if r.Alive() {
+		return counter - r.Birth
+	}
+	return r.Death - r.Birth
+}
+
+// Lineage stores the ancestry of all the cells which lived in a world, in order of birth.
+// A divided cell is the parent of its two children.
+type Lineage struct {
+	records []LineageRecord
+	index   map[string]int
+}
+
+// NewLineage creates an empty lineage store.
+func NewLineage() *Lineage {
+	return &Lineage{index: map[string]int{}}
+}
+
+// newLineageFrom creates a lineage store holding some records.
+func newLineageFrom(records []LineageRecord) *Lineage {
+	l := NewLineage()
+	for _, r := range records {
+		l.add(r)
+	}
+	return l
+}
+
+func (l *Lineage) add(r LineageRecord) {
+	l.index[r.ID] = len(l.records)
+	l.records = append(l.records, r)
+}
+
+// Born records the birth of a cell.
+func (l *Lineage) Born(w *World, c *cell.Cell) {
+	l.add(LineageRecord{ID: c.ID(), Parent: c.Parent(), Birth: c.Birth()})
+}
+
+// Died records the death of a cell.
+func (l *Lineage) Died(w *World, c *cell.Cell) {
+	i, ok := l.index[c.ID()]
+	if !ok {
+		return
+	}
+	l.records[i].Death = c.Death()
+	l.records[i].Cause = c.DeathCause()
+	l.records[i].Predator = c.Predator()
+}
+
+// Records returns a copy of the records, in order of birth.
+func (l *Lineage) Records() []LineageRecord {
+	records := make([]LineageRecord, len(l.records))
+	copy(records, l.records)
+	return records
+}
+
+// Record returns the record of a cell, and false if the cell is unknown.
+func (l *Lineage) Record(id string) (LineageRecord, bool) {
+	i, ok := l.index[id]
+	if !ok {
+		return LineageRecord{}, false
+	}
+	return l.records[i], true
+}
+
+// Survivors returns the lineage restricted to the living cells and their ancestors,
+// the lineages which went extinct being pruned.
+func (l *Lineage) Survivors() *Lineage {
+	keep := make([]bool, len(l.records))
+	for i, r := range l.records {
+		if !r.Alive() {
+			continue
+		}
+		// walk up to the first ancestor already kept
+		for j, ok := i, true; ok && !keep[j]; j, ok = l.index[l.records[j].Parent] {
+			keep[j] = true
+		}
+	}
+	s := NewLineage()
+	for i, r := range l.records {
+		if keep[i] {
+			s.add(r)
+		}
+	}
+	return s
+}
+
+// tree returns the indexes of the records without any known parent, and the children of each record.
+func (l *Lineage) tree() ([]int, map[int][]int) {
+	roots := []int{}
+	children := map[int][]int{}
+	for i, r := range l.records {
+		if p, ok := l.index[r.Parent]; ok {
+			children[p] = append(children[p], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	return roots, children
+}
+
+// LineageFormat is a lineage export format.
+type LineageFormat int
+
+const (
+	// Newick is the parenthesized tree format read by phylogenetic tools.
+	Newick LineageFormat = iota
+	// DOT is the GraphViz graph format.
+	DOT
+)
+
+// LineageFormatFromPath returns the format of a lineage file from its extension,
+// DOT for ".dot" and ".gv" files and Newick otherwise.
+func LineageFormatFromPath(path string) LineageFormat {
+	switch filepath.Ext(path) {
+	case ".dot", ".gv":
+		return DOT
+	}
+	return Newick
+}
+
+// Write exports the lineage in a given format. Branches are as long as the cells lived,
+// up to a given tick for living cells.
+func (l *Lineage) Write(wr io.Writer, f LineageFormat, counter int) error {
+	bw := bufio.NewWriter(wr)
+	if f == DOT {
+		l.writeDOT(bw, counter)
+	} else {
+		l.writeNewick(bw, counter)
+	}
+	return bw.Flush()
+}
+
+// Save writes the lineage to a file, in the format given by its extension.
+func (l *Lineage) Save(path string, counter int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := l.Write(f, LineageFormatFromPath(path), counter); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeNewick writes the lineage as a Newick tree, each cell being labeled with its ID.
+// Cells without any parent are the children of an unlabeled root when there are several of them.
+func (l *Lineage) writeNewick(wr *bufio.Writer, counter int) {
+	roots, children := l.tree()
+	var write func(i int)
+	write = func(i int) {
+		if kids := children[i]; len(kids) > 0 {
+			wr.WriteByte('(')
+			for k, child := range kids {
+				if k > 0 {
+					wr.WriteByte(',')
+				}
+				write(child)
+			}
+			wr.WriteByte(')')
+		}
+		r := l.records[i]
+		fmt.Fprintf(wr, "%s:%d", r.ID, r.lifespan(counter))
+	}
+	if len(roots) != 1 {
+		wr.WriteByte('(')
+	}
+	for k, root := range roots {
+		if k > 0 {
+			wr.WriteByte(',')
+		}
+		write(root)
+	}
+	if len(roots) != 1 {
+		wr.WriteByte(')')
+	}
+	wr.WriteString(";\n")
+}
+
+// writeDOT writes the lineage as a GraphViz directed graph from parents to children, each cell being
+// labeled with the first characters of its ID, its lifetime, its death cause and its predator.
+func (l *Lineage) writeDOT(wr *bufio.Writer, counter int) {
+	wr.WriteString("digraph lineage {\n")
+	wr.WriteString("  node [shape=box, fontsize=10];\n")
+	for _, r := range l.records {
+		end, style := "alive", "bold"
+		if !r.Alive() {
+			end, style = fmt.Sprintf("%d %s", r.Death, r.Cause), "solid"
+			if r.Predator != "" {
+				end += " by " + cell.ShortID(r.Predator)
+			}
+		}
+		fmt.Fprintf(wr, "  %q [label=%q, style=%s];\n", r.ID, fmt.Sprintf("%s\n%d-%s", cell.ShortID(r.ID), r.Birth, end), style)
+	}
+	for _, r := range l.records {
+		if _, ok := l.index[r.Parent]; ok {
+			fmt.Fprintf(wr, "  %q -> %q;\n", r.Parent, r.ID)
+		}
+	}
+	wr.WriteString("}\n")
+}
+
+// Lineage returns the ancestry of the cells which lived in the world.
+func (w *World) Lineage() *Lineage {
+	return w.lineage
+}
@@ -0,0 +1,123 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// Cells of the test lineage: a divided into b and c, c was eaten by b, d is alive and e starved.
+const (
+	idA = "aaaaaaaa-0000-4000-8000-000000000000"
+	idB = "bbbbbbbb-0000-4000-8000-000000000000"
+	idC = "cccccccc-0000-4000-8000-000000000000"
+	idD = "dddddddd-0000-4000-8000-000000000000"
+	idE = "eeeeeeee-0000-4000-8000-000000000000"
+)
+
+func testLineage() *Lineage {
+	return newLineageFrom([]LineageRecord{
+		{ID: idA, Birth: 0, Death: 100, Cause: cell.Division},
+		{ID: idD, Birth: 10},
+		{ID: idE, Birth: 20, Death: 30, Cause: cell.Starvation},
+		{ID: idB, Parent: idA, Birth: 100},
+		{ID: idC, Parent: idA, Birth: 100, Death: 150, Cause: cell.Predation, Predator: idB},
+	})
+}
+
+func write(t *testing.T, l *Lineage, f LineageFormat) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := l.Write(&buf, f, 200); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestLineageNewick(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		lineage *Lineage
+		newick  string
+	}{
+		{"several roots", testLineage(), "((" + idB + ":100," + idC + ":50)" + idA + ":100," + idD + ":190," + idE + ":10);\n"},
+		{"single root", newLineageFrom(testLineage().Records()[3:]), "(" + idB + ":100," + idC + ":50);\n"},
+		{"survivors", testLineage().Survivors(), "((" + idB + ":100)" + idA + ":100," + idD + ":190);\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if newick := write(t, tc.lineage, Newick); newick != tc.newick {
+				t.Errorf("lineage exported as %s, expected %s", newick, tc.newick)
+			}
+		})
+	}
+	if newick := write(t, newLineageFrom(testLineage().Records()[:1]), Newick); newick != idA+":100;\n" {
+		t.Errorf("lineage of a single cell exported as %s", newick)
+	}
+}
+
+func TestLineageDOT(t *testing.T) {
+	dot := write(t, testLineage(), DOT)
+	if !strings.HasPrefix(dot, "digraph lineage {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("lineage exported as %s, expected a digraph", dot)
+	}
+	for _, line := range []string{
+		`"` + idA + `" [label="aaaaaaaa\n0-100 division", style=solid];`,
+		`"` + idB + `" [label="bbbbbbbb\n100-alive", style=bold];`,
+		`"` + idC + `" [label="cccccccc\n100-150 predation by bbbbbbbb", style=solid];`,
+		`"` + idA + `" -> "` + idB + `";`,
+		`"` + idA + `" -> "` + idC + `";`,
+	} {
+		if !strings.Contains(dot, "  "+line+"\n") {
+			t.Errorf("lineage exported as %s, expected it to hold %s", dot, line)
+		}
+	}
+	if edges := strings.Count(dot, "->"); edges != 2 {
+		t.Errorf("%d edges, expected 2", edges)
+	}
+}
+
+func TestLineageSurvivors(t *testing.T) {
+	l := testLineage()
+	s := l.Survivors()
+	ids := []string{}
+	for _, r := range s.Records() {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, " ") != strings.Join([]string{idA, idD, idB}, " ") {
+		t.Errorf("survivors %v, expected a, d and b in order of birth", ids)
+	}
+	if _, ok := s.Record(idE); ok {
+		t.Error("extinct lineage kept")
+	}
+	if len(l.Records()) != 5 {
+		t.Error("pruning changed the lineage")
+	}
+}
+
+func TestWorldLineage(t *testing.T) {
+	w := New(testConfig(), 9)
+	step(t, w, testTicks)
+	records := w.Lineage().Records()
+	for _, c := range w.Cells() {
+		r, ok := w.Lineage().Record(c.ID())
+		if !ok {
+			t.Fatalf("cell %s is not part of the lineage", c.ID())
+		}
+		if r.Parent != c.Parent() || r.Birth != c.Birth() || r.Alive() == c.IsDead() {
+			t.Errorf("record %+v does not match its cell", r)
+		}
+	}
+	for _, r := range records {
+		if r.Parent == "" {
+			continue
+		}
+		if p, ok := w.Lineage().Record(r.Parent); !ok || p.Birth > r.Birth {
+			t.Errorf("record %+v has no parent born before it", r)
+		}
+	}
+	if len(w.Lineage().Survivors().Records()) > len(records) {
+		t.Error("survivors outnumber the lineage")
+	}
+}
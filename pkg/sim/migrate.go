@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// Snapshots of older versions are migrated one version after the other: version 2 gathered the world
+// parameters in a config, version 3 grew food pellets and version 4 gave diets to cells.
+
+// legacyFields holds the fields of an older snapshot version which are not part of the current one anymore.
+type legacyFields struct {
+	v1 snapshotV1
+	v3 snapshotV3
+}
+
+// snapshotV1 holds the fields of version 1 snapshots moved since.
+type snapshotV1 struct {
+	// Width, Height, TileDimension, Spawner and Collisions were gathered in Config by version 2.
+	Width         int
+	Height        int
+	TileDimension int
+	Spawner       Spawner
+	Collisions    bool
+	// Cells genomes held their traits before version 2 grouped them.
+	Cells []struct {
+		Genome cell.Traits
+	}
+}
+
+// snapshotV3 holds the cells efficiencies of version 2 and 3 snapshots, given by their diets since version 4.
+type snapshotV3 struct {
+	Config struct {
+		Cell struct {
+			EatEfficiency  float64
+			FoodEfficiency float64
+		}
+	}
+}
+
+// migrate returns the snapshot migrated to the current version, leaving s untouched.
+func (s *Snapshot) migrate() (*Snapshot, error) {
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected at most %d", s.Version, SnapshotVersion)
+	}
+	m := *s
+	m.Cells = append([]cell.State{}, s.Cells...)
+	if m.Version == 1 {
+		m.migrateV1()
+	}
+	if m.Version == 2 {
+		m.migrateV2()
+	}
+	if m.Version == 3 {
+		m.migrateV3()
+	}
+	return &m, nil
+}
+
+// migrateV1 gathers the world parameters of a version 1 snapshot in a config, the other parameters
+// having their default value, and the traits of its cells in their genome.
+func (s *Snapshot) migrateV1() {
+	v1 := s.legacy.v1
+	s.Config = DefaultConfig()
+	s.Config.Width, s.Config.Height, s.Config.TileDimension = v1.Width, v1.Height, v1.TileDimension
+	s.Config.Spawner = v1.Spawner
+	s.Config.Collisions = v1.Collisions
+	for i := range s.Cells {
+		if i < len(v1.Cells) {
+			s.Cells[i].Genome.Traits = v1.Cells[i].Genome
+		}
+	}
+	// the brain of cells without any parent was only given on the command line
+	for _, c := range s.Cells {
+		if c.Genome.Brain.Enabled() {
+			s.Config.Cell.SetLegacyBrain(c.Genome.Brain)
+			break
+		}
+	}
+	// cells gained half the energy of their prey
+	s.legacy.v3.Config.Cell.EatEfficiency = 0.5
+	s.Version = 2
+}
+
+// migrateV2 makes the brains of the cells of a version 2 snapshot blind to pellets.
+// Its world keeps growing none, its food config being empty.
+func (s *Snapshot) migrateV2() {
+	for i := range s.Cells {
+		s.Cells[i].Genome = s.Cells[i].Genome.AddFoodInputs()
+	}
+	s.Version = 3
+}
+
+// migrateV3 makes omnivores of all the cells of a version 3 snapshot, with its eating efficiencies,
+// as they used to eat both cells and pellets.
+func (s *Snapshot) migrateV3() {
+	v3 := s.legacy.v3.Config.Cell
+	s.Config.Cell.Diets = cell.DietsConfig{
+		Omnivore: cell.DietConfig{Share: 1, CellEfficiency: v3.EatEfficiency, FoodEfficiency: v3.FoodEfficiency},
+	}
+	for i := range s.Cells {
+		s.Cells[i].Genome.Diet = cell.Omnivore
+	}
+	s.Version = 4
+}
@@ -0,0 +1,18 @@
+package sim
+
+import "github.com/jtbonhomme/golife/pkg/neat"
+
+// Innovations returns the registry of the structural mutations of the cells NEAT networks,
+// created on first use.
+func (w *World) Innovations() *neat.Innovations {
+	if w.innovations == nil {
+		w.innovations = w.config.Cell.NewInnovations()
+	}
+	return w.innovations
+}
+
+// SetInnovations makes the world register the structural mutations of the cells NEAT networks in an
+// existing registry, for the networks of genomes mutated elsewhere to keep consistent innovations.
+func (w *World) SetInnovations(innovations *neat.Innovations) {
+	w.innovations = innovations
+}
@@ -0,0 +1,32 @@
+package sim
+
+import "github.com/jtbonhomme/golife/pkg/cell"
+
+// Observer is notified of the births and deaths happening in a world.
+type Observer interface {
+	// Born is called when a cell joins the world, spontaneously generated or born from a division.
+	Born(w *World, c *cell.Cell)
+	// Died is called when a dead cell is removed from the world.
+	Died(w *World, c *cell.Cell)
+}
+
+// AddObserver registers an observer notified of the world births and deaths.
+func (w *World) AddObserver(o Observer) {
+	w.observers = append(w.observers, o)
+}
+
+func (w *World) notifyBirth(c *cell.Cell) {
+	w.lineage.Born(w, c)
+	logEvent(birthEvent(w, c))
+	for _, o := range w.observers {
+		o.Born(w, c)
+	}
+}
+
+func (w *World) notifyDeath(c *cell.Cell) {
+	w.lineage.Died(w, c)
+	logEvent(deathEvent(c))
+	for _, o := range w.observers {
+		o.Died(w, c)
+	}
+}
@@ -0,0 +1,224 @@
+package sim
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReplayVersion is the version of the replay format written by this package.
+const ReplayVersion int = 1
+
+// ErrDiverged is the error returned when a replayed run does not match a recorded checkpoint.
+var ErrDiverged = errors.New("replay diverged")
+
+// Checkpoint is the hash of a world state at a given tick.
+type Checkpoint struct {
+	Tick int    `json:"tick"`
+	Hash string `json:"hash"`
+}
+
+// Replay is the record of a whole run: its initial state, the external inputs applied to it,
+// and the hashes of its state at regular checkpoints.
+type Replay struct {
+	Version     int          `json:"version"`
+	Initial     *Snapshot    `json:"initial"`
+	Inputs      []Input      `json:"inputs"`
+	Checkpoints []Checkpoint `json:"checkpoints"`
+}
+
+// Hash returns a hash of the world state. Parameters are left out, their changes being recorded as inputs,
+// for the hash not to depend on how empty lists are serialized, and so is the lineage, which only grows
+// with the history of the cells.
+// It fails if the state can not be serialized, e.g. when a cell holds a NaN value.
+func (w *World) Hash() (string, error) {
+	s := w.Snapshot()
+	s.Config = Config{}
+	s.Lineage = nil
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("can not hash world state at tick %d: %w", w.Counter(), err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Recorder records a run in a replay.
+type Recorder struct {
+	replay *Replay
+	every  int
+}
+
+// NewRecorder starts recording a run from the current state of a world,
+// with a checkpoint every given number of ticks.
+func NewRecorder(w *World, every int) *Recorder {
+	r := &Recorder{every: every}
+	r.Restart(w)
+	return r
+}
+
+// Restart discards the run recorded so far, and starts recording again from the current state of a world.
+func (r *Recorder) Restart(w *World) {
+	r.replay = &Replay{
+		Version:     ReplayVersion,
+		Initial:     w.Snapshot(),
+		Inputs:      []Input{},
+		Checkpoints: []Checkpoint{},
+	}
+}
+
+// Record records an input applied to the world.
+func (r *Recorder) Record(in Input) {
+	r.replay.Inputs = append(r.replay.Inputs, in)
+}
+
+// Checkpoint records the hash of the world state if its counter is a multiple of the checkpoint period.
+func (r *Recorder) Checkpoint(w *World) error {
+	if r.every <= 0 || w.Counter()%r.every != 0 {
+		return nil
+	}
+	hash, err := w.Hash()
+	if err != nil {
+		return err
+	}
+	r.replay.Checkpoints = append(r.replay.Checkpoints, Checkpoint{Tick: w.Counter(), Hash: hash})
+	return nil
+}
+
+// Replay returns the replay recorded so far.
+func (r *Recorder) Replay() *Replay {
+	return r.replay
+}
+
+// Player replays a recorded run, applying its inputs and verifying its checkpoints.
+type Player struct {
+	replay     *Replay
+	input      int
+	checkpoint int
+}
+
+// NewPlayer creates the initial world of a replay and a player driving it.
+func NewPlayer(r *Replay) (*World, *Player, error) {
+	if r.Version != ReplayVersion {
+		return nil, nil, fmt.Errorf("unsupported replay version %d, expected %d", r.Version, ReplayVersion)
+	}
+	if r.Initial == nil {
+		return nil, nil, fmt.Errorf("replay without initial state")
+	}
+	w, err := Restore(r.Initial)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, &Player{replay: r}, nil
+}
+
+// Apply applies the inputs recorded at the current tick of the world.
+func (p *Player) Apply(w *World) error {
+	for ; p.input < len(p.replay.Inputs); p.input++ {
+		in := p.replay.Inputs[p.input]
+		if in.Tick > w.Counter() {
+			break
+		}
+		if err := w.Apply(in); err != nil {
+			return fmt.Errorf("can not apply %s input at tick %d: %w", in.Kind, in.Tick, err)
+		}
+	}
+	return nil
+}
+
+// Verify compares the world state to the checkpoint recorded at its current tick, if any.
+// It returns true if a checkpoint has been verified, and an error wrapping ErrDiverged if the state differs.
+func (p *Player) Verify(w *World) (bool, error) {
+	if p.checkpoint >= len(p.replay.Checkpoints) || p.replay.Checkpoints[p.checkpoint].Tick != w.Counter() {
+		return false, nil
+	}
+	cp := p.replay.Checkpoints[p.checkpoint]
+	p.checkpoint++
+	hash, err := w.Hash()
+	if err != nil {
+		return false, err
+	}
+	if hash != cp.Hash {
+		return false, fmt.Errorf("%w at tick %d: state hash %s, recorded %s", ErrDiverged, cp.Tick, hash, cp.Hash)
+	}
+	return true, nil
+}
+
+// Step applies the inputs of the current tick, advances the world and verifies its state.
+func (p *Player) Step(w *World) error {
+	if err := p.Apply(w); err != nil {
+		return err
+	}
+	if err := w.Step(); err != nil {
+		return err
+	}
+	_, err := p.Verify(w)
+	return err
+}
+
+// Done returns true once all inputs have been applied and all checkpoints verified.
+func (p *Player) Done() bool {
+	return p.input >= len(p.replay.Inputs) && p.checkpoint >= len(p.replay.Checkpoints)
+}
+
+// Checkpoints returns the number of checkpoints verified so far.
+func (p *Player) Checkpoints() int {
+	return p.checkpoint
+}
+
+// Write serializes the replay in a given format.
+func (r *Replay) Write(wr io.Writer, f Format) error {
+	if f == JSON {
+		enc := json.NewEncoder(wr)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	zw := gzip.NewWriter(wr)
+	if err := gob.NewEncoder(zw).Encode(r); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadReplay deserializes a replay written in a given format.
+func ReadReplay(r io.Reader, f Format) (*Replay, error) {
+	rp := &Replay{}
+	if f == JSON {
+		return rp, json.NewDecoder(r).Decode(rp)
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return rp, gob.NewDecoder(zr).Decode(rp)
+}
+
+// Save writes the replay to a file, in the format given by its extension.
+func (r *Replay) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f, FormatFromPath(path)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadReplay reads a replay from a file, in the format given by its extension.
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadReplay(f, FormatFromPath(path))
+}
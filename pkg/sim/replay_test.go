@@ -0,0 +1,111 @@
+package sim
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+)
+
+// checkpointPeriod is the number of ticks between the checkpoints of the runs recorded by tests.
+const checkpointPeriod = 50
+
+func hash(t *testing.T, w *World) string {
+	t.Helper()
+	h, err := w.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestSameSeedSameHash(t *testing.T) {
+	a := New(testConfig(), 42)
+	b := New(testConfig(), 42)
+	step(t, a, testTicks)
+	step(t, b, testTicks)
+	if hash(t, a) != hash(t, b) {
+		t.Errorf("worlds of the same seed have different hashes after %d ticks", testTicks)
+	}
+	c := New(testConfig(), 43)
+	step(t, c, testTicks)
+	if hash(t, a) == hash(t, c) {
+		t.Errorf("worlds of different seeds have the same hash after %d ticks", testTicks)
+	}
+}
+
+// record runs a world recording spawn, parameter change and despawn inputs.
+func record(t *testing.T) *Replay {
+	t.Helper()
+	w := New(testConfig(), 11)
+	r := NewRecorder(w, checkpointPeriod)
+	apply := func(in Input) {
+		t.Helper()
+		in.Tick = w.Counter()
+		if err := w.Apply(in); err != nil {
+			t.Fatal(err)
+		}
+		r.Record(in)
+	}
+	for w.Counter() < testTicks {
+		switch w.Counter() {
+		case 100:
+			apply(Input{Kind: SpawnInput, Position: &vector.Vector2D{X: 320, Y: 240}})
+		case 210:
+			config := w.Config()
+			config.Food.Rate *= 2
+			apply(Input{Kind: ConfigInput, Config: &config})
+		case 300:
+			apply(Input{Kind: DespawnInput, Cell: w.Cells()[0].ID()})
+		}
+		step(t, w, 1)
+		if err := r.Checkpoint(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r.Replay()
+}
+
+func TestReplayToCheckpoint(t *testing.T) {
+	replay := record(t)
+	if len(replay.Checkpoints) != testTicks/checkpointPeriod {
+		t.Fatalf("%d checkpoints recorded, expected %d", len(replay.Checkpoints), testTicks/checkpointPeriod)
+	}
+
+	w, p, err := NewPlayer(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !p.Done() {
+		if err := p.Step(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.Checkpoints() != len(replay.Checkpoints) {
+		t.Errorf("%d checkpoints verified, expected %d", p.Checkpoints(), len(replay.Checkpoints))
+	}
+}
+
+func TestReplayDiverged(t *testing.T) {
+	replay := record(t)
+	// without the parameter change at tick 210, the run goes its own way from the next checkpoint
+	replay.Inputs = append(replay.Inputs[:1], replay.Inputs[2:]...)
+
+	w, p, err := NewPlayer(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !p.Done() {
+		err = p.Step(w)
+		if err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, ErrDiverged) {
+		t.Fatalf("replay missing an input ended with %v, expected a divergence", err)
+	}
+	if w.Counter() != 250 || !strings.Contains(err.Error(), "at tick 250:") {
+		t.Errorf("replay diverged at tick %d (%s), expected 250", w.Counter(), err)
+	}
+}
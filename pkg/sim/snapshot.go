@@ -0,0 +1,192 @@
+package sim
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/food"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// SnapshotVersion is the version of the snapshot format written by this package.
+const SnapshotVersion int = 4
+
+// Snapshot is the serializable state of a whole world.
+type Snapshot struct {
+	Version int           `json:"version"`
+	Seed    int64         `json:"seed"`
+	Draws   uint64        `json:"draws"`
+	Counter int           `json:"counter"`
+	Config  Config        `json:"config"`
+	Cells   []cell.State  `json:"cells"`
+	Food    []food.Pellet `json:"food"`
+	// Innovations is the registry of the cells NEAT networks, if any.
+	Innovations *neat.Innovations `json:"innovations,omitempty"`
+	// Lineage is the ancestry of the cells which lived in the world.
+	Lineage []LineageRecord `json:"lineage,omitempty"`
+
+	// legacy holds the fields of an older version read along the snapshot, for it to be migrated.
+	legacy legacyFields
+}
+
+// Format is a snapshot serialization format.
+type Format int
+
+const (
+	// JSON is a human readable format.
+	JSON Format = iota
+	// Binary is a compact format, made of gzipped gob.
+	Binary
+)
+
+// FormatFromPath returns the format of a snapshot file from its extension,
+// JSON for ".json" files and Binary otherwise.
+func FormatFromPath(path string) Format {
+	if filepath.Ext(path) == ".json" {
+		return JSON
+	}
+	return Binary
+}
+
+// Snapshot returns the current state of the world.
+func (w *World) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Version: SnapshotVersion,
+		Seed:    w.src.seed,
+		Draws:   w.src.draws,
+		Counter: w.counter,
+		Config:  w.config,
+		Cells:   make([]cell.State, 0, len(w.cells)),
+		Food:    make([]food.Pellet, 0, len(w.pellets)),
+	}
+	for _, c := range w.cells {
+		s.Cells = append(s.Cells, c.State())
+	}
+	for _, p := range w.pellets {
+		s.Food = append(s.Food, *p)
+	}
+	if w.innovations != nil {
+		s.Innovations = w.innovations.Copy()
+	}
+	s.Lineage = w.lineage.Records()
+	return s
+}
+
+// Restore creates a world from a snapshot. The restored world evolves exactly as the saved one would have.
+// Snapshots of older versions are migrated first.
+func Restore(s *Snapshot) (*World, error) {
+	s, err := s.migrate()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Config.Validate(); err != nil {
+		return nil, err
+	}
+	w := newWorld(s.Config, newSource(s.Seed, s.Draws))
+	w.counter = s.Counter
+	for _, state := range s.Cells {
+		c, err := cell.Restore(w.rnd, &w.config.Cell, state, w.Width, w.Height, w)
+		if err != nil {
+			return nil, fmt.Errorf("can not restore cell %s: %w", state.ID, err)
+		}
+		w.cells = append(w.cells, c)
+	}
+	for i := range s.Food {
+		p := s.Food[i]
+		w.pellets = append(w.pellets, &p)
+	}
+	if s.Innovations != nil {
+		w.innovations = s.Innovations.Copy()
+	}
+	if len(s.Lineage) > 0 {
+		w.lineage = newLineageFrom(s.Lineage)
+	} else {
+		// snapshots saved before lineages were recorded, up to version 3, only know the living cells
+		for _, c := range w.cells {
+			w.lineage.Born(w, c)
+		}
+	}
+	w.indexCells()
+	return w, nil
+}
+
+// Write serializes the snapshot in a given format.
+func (s *Snapshot) Write(wr io.Writer, f Format) error {
+	if f == JSON {
+		enc := json.NewEncoder(wr)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+	zw := gzip.NewWriter(wr)
+	if err := gob.NewEncoder(zw).Encode(s); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadSnapshot deserializes a snapshot written in a given format, along with the fields of its version
+// moved since, if it is an older one.
+func ReadSnapshot(r io.Reader, f Format) (*Snapshot, error) {
+	if f == Binary {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	// older versions are decoded twice, as a snapshot and as their legacy fields
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := decode(b, f, s); err != nil {
+		return nil, err
+	}
+	switch s.Version {
+	case 1:
+		err = decode(b, f, &s.legacy.v1)
+	case 2, 3:
+		err = decode(b, f, &s.legacy.v3)
+	}
+	return s, err
+}
+
+// decode deserializes a value written in a given format.
+func decode(b []byte, f Format, v interface{}) error {
+	if f == JSON {
+		return json.Unmarshal(b, v)
+	}
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+// Save writes the snapshot to a file, in the format given by its extension.
+func (s *Snapshot) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(f, FormatFromPath(path)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadSnapshot reads a snapshot from a file, in the format given by its extension.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSnapshot(f, FormatFromPath(path))
+}
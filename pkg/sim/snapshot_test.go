@@ -0,0 +1,134 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// roundTrip writes a snapshot of a world in a given format and restores a world from it.
+func roundTrip(t *testing.T, w *World, f Format) *World {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := w.Snapshot().Write(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	s, err := ReadSnapshot(&buf, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Restore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return restored
+}
+
+// snapshotJSON returns the serialized state of a world without its parameters,
+// gob decoding their empty lists as nil ones.
+func snapshotJSON(t *testing.T, w *World) []byte {
+	t.Helper()
+	s := w.Snapshot()
+	s.Config = Config{}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+	}{
+		{"json", JSON},
+		{"binary", Binary},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := New(testConfig(), 7)
+			step(t, w, testTicks)
+			restored := roundTrip(t, w, tc.format)
+
+			saved, loaded := snapshotJSON(t, w), snapshotJSON(t, restored)
+			if restored.Config().Width != w.Config().Width || restored.Config().Cell.MaxSize != w.Config().Cell.MaxSize {
+				t.Error("restored parameters differ from the saved ones")
+			}
+			if !bytes.Equal(saved, loaded) {
+				t.Fatal("restored world differs from the saved one")
+			}
+
+			// the restored world goes on as the saved one
+			step(t, w, testTicks)
+			step(t, restored, testTicks)
+			if state(restored) != state(w) {
+				t.Errorf("restored world diverged from the saved one after %d ticks", testTicks)
+			}
+		})
+	}
+}
+
+// TestRestoreOlderVersions restores snapshots saved by the previous versions of the format, of worlds of 320x240
+// run for 200 ticks with collisions and cells driven by brains seeing 3 rays.
+func TestRestoreOlderVersions(t *testing.T) {
+	for version := 1; version < SnapshotVersion; version++ {
+		worlds := map[string]*World{}
+		for _, ext := range []string{"json", "bin"} {
+			path := fmt.Sprintf("testdata/snapshot-v%d.%s", version, ext)
+			t.Run(path, func(t *testing.T) {
+				s, err := LoadSnapshot(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.Version != version {
+					t.Fatalf("snapshot of version %d, expected %d", s.Version, version)
+				}
+				w, err := Restore(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.Version != version {
+					t.Error("restoring the snapshot migrated it")
+				}
+				config := w.Config()
+				if config.Width != 320 || config.Height != 240 || !config.Collisions || config.Cell.VisionRays != 3 {
+					t.Errorf("parameters %+v were not migrated", config)
+				}
+				if w.Counter() != 200 || len(w.Cells()) != len(s.Cells) || len(w.Cells()) == 0 {
+					t.Fatalf("restored %d cells at tick %d from %d cells at tick 200", len(w.Cells()), w.Counter(), len(s.Cells))
+				}
+				for _, c := range w.Cells() {
+					g := c.Genome()
+					if g.DetectionRadius <= 0 || g.Diet != cell.Omnivore || !g.Brain.Enabled() {
+						t.Fatalf("genome %+v was not migrated", g)
+					}
+					if err := config.Cell.CheckGenome(g); err != nil {
+						t.Fatal(err)
+					}
+					if c.Lineage() == "" {
+						t.Errorf("cell %s has no lineage", c.ID())
+					}
+					if _, ok := w.Lineage().Record(c.ID()); !ok {
+						t.Errorf("cell %s is not part of the world lineage", c.ID())
+					}
+				}
+				step(t, w, testTicks)
+				worlds[ext] = w
+			})
+		}
+		if w, ok := worlds["json"]; ok && worlds["bin"] != nil && state(w) != state(worlds["bin"]) {
+			t.Errorf("worlds restored from version %d snapshots of both formats differ", version)
+		}
+	}
+}
+
+func TestRestoreNewerVersion(t *testing.T) {
+	s := New(testConfig(), 7).Snapshot()
+	s.Version = SnapshotVersion + 1
+	if _, err := Restore(s); err == nil {
+		t.Error("snapshot of a newer version restored")
+	}
+}
@@ -0,0 +1,31 @@
+package sim
+
+import "math/rand"
+
+// source is a random source counting the values it draws,
+// so that its state can be saved and restored.
+type source struct {
+	src   rand.Source
+	seed  int64
+	draws uint64
+}
+
+func newSource(seed int64, draws uint64) *source {
+	s := &source{}
+	s.Seed(seed)
+	for s.draws < draws {
+		s.Int63()
+	}
+	return s
+}
+
+func (s *source) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *source) Seed(seed int64) {
+	s.src = rand.NewSource(seed)
+	s.seed = seed
+	s.draws = 0
+}
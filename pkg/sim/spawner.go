@@ -0,0 +1,54 @@
+package sim
+
+import (
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+const (
+	defaultSpawnRate  float64 = 0.005
+	defaultSpawnFloor int     = 10
+)
+
+// Spawner spontaneously generates new cells, without any parent.
+type Spawner struct {
+	// Rate is the probability for a new cell to be generated at each tick.
+	Rate float64 `json:"rate"`
+	// Floor is the population under which new cells are generated until it is reached again.
+	Floor int `json:"floor"`
+}
+
+// DefaultSpawner returns a spawner preventing the world extinction.
+func DefaultSpawner() Spawner {
+	return Spawner{
+		Rate:  defaultSpawnRate,
+		Floor: defaultSpawnFloor,
+	}
+}
+
+// Enabled returns true if the spawner ever generates cells.
+func (s Spawner) Enabled() bool {
+	return s.Rate > 0 || s.Floor > 0
+}
+
+// spawn generates the cells required by the spawner. An enabled spawner always leaves at least one cell,
+// for the world never to go extinct.
+func (w *World) spawn() {
+	for len(w.cells) < w.config.Spawner.Floor {
+		w.spawnCell()
+	}
+	if w.config.Spawner.Rate > 0 && w.rnd.Float64() < w.config.Spawner.Rate {
+		w.spawnCell()
+	}
+	if len(w.cells) == 0 && w.config.Spawner.Enabled() {
+		w.spawnCell()
+	}
+}
+
+// spawnCell adds a new random cell at a random position.
+func (w *World) spawnCell() *cell.Cell {
+	return w.SpawnAt(vector.Vector2D{
+		X: float64(w.rnd.Int31n(int32(w.Width))),
+		Y: float64(w.rnd.Int31n(int32(w.Height))),
+	})
+}
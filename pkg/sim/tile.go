@@ -0,0 +1,45 @@
+package sim
+
+import (
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/food"
+)
+
+// Tile is a square area of the world referencing the cells and pellets located in it.
+// Tiles form a uniform grid used as a spatial index for neighbor queries.
+type Tile struct {
+	x      int
+	y      int
+	width  float64
+	height float64
+	cells  []*cell.Cell
+	food   []*food.Pellet
+}
+
+func (t *Tile) ResetCellCount() {
+	t.cells = t.cells[:0]
+	t.food = t.food[:0]
+}
+
+func (t *Tile) AddCell(c *cell.Cell) {
+	t.cells = append(t.cells, c)
+}
+
+func (t *Tile) CellCount() int {
+	return len(t.cells)
+}
+
+// Cells returns the cells located in the tile.
+func (t *Tile) Cells() []*cell.Cell {
+	return t.cells
+}
+
+// AddFood references a pellet located in the tile.
+func (t *Tile) AddFood(p *food.Pellet) {
+	t.food = append(t.food, p)
+}
+
+// Food returns the pellets located in the tile.
+func (t *Tile) Food() []*food.Pellet {
+	return t.food
+}
@@ -0,0 +1,286 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/golife/internal/vector"
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/food"
+	"github.com/jtbonhomme/golife/pkg/neat"
+)
+
+// World owns the simulation state and advances it tick after tick,
+// without any dependency to a display.
+type World struct {
+	counter       int
+	src           *source
+	rnd           *rand.Rand
+	cells         []*cell.Cell
+	pellets       []*food.Pellet
+	tiles         [][]*Tile
+	config        Config
+	observers     []Observer
+	innovations   *neat.Innovations
+	lineage       *Lineage
+	TileDimension int
+	Width         int
+	Height        int
+}
+
+// New creates a world from a config, populated with random cells.
+// Two worlds created with the same config and seed evolve identically.
+func New(config Config, seed int64) *World {
+	return NewWithGenomes(config, seed, nil)
+}
+
+// NewWithGenomes creates a world from a config, whose first cells carry given genomes, at random positions.
+// Random cells complete the population, if needed.
+func NewWithGenomes(config Config, seed int64, genomes []cell.Genome) *World {
+	w := newWorld(config, newSource(seed, 0))
+	for _, g := range genomes {
+		w.SpawnGenome(vector.Vector2D{
+			X: float64(w.rnd.Int31n(int32(w.Width))),
+			Y: float64(w.rnd.Int31n(int32(w.Height))),
+		}, g)
+	}
+	for i := len(genomes); i < config.Population; i++ {
+		w.spawnCell()
+	}
+	w.indexCells()
+	return w
+}
+
+// newWorld creates an empty world drawing its random values from src.
+func newWorld(config Config, src *source) *World {
+	w := &World{
+		counter:       0,
+		src:           src,
+		rnd:           rand.New(src),
+		config:        config,
+		Width:         config.Width,
+		Height:        config.Height,
+		TileDimension: config.TileDimension,
+		cells:         []*cell.Cell{},
+		lineage:       NewLineage(),
+	}
+	// the last row and column of tiles may overflow the world
+	cols := (w.Width + w.TileDimension - 1) / w.TileDimension
+	rows := (w.Height + w.TileDimension - 1) / w.TileDimension
+	w.tiles = make([][]*Tile, cols)
+	for i := 0; i < cols; i++ {
+		w.tiles[i] = make([]*Tile, rows)
+		for j := 0; j < rows; j++ {
+			w.tiles[i][j] = &Tile{x: i, y: j, width: float64(w.TileDimension), height: float64(w.TileDimension), cells: []*cell.Cell{}}
+		}
+	}
+	return w
+}
+
+// removeDeadCells removes dead cells, preserving the order of the living ones.
+func (w *World) removeDeadCells() {
+	alive := w.cells[:0]
+	for _, c := range w.cells {
+		if !c.IsDead() {
+			alive = append(alive, c)
+			continue
+		}
+		w.notifyDeath(c)
+	}
+	for i := len(alive); i < len(w.cells); i++ {
+		w.cells[i] = nil
+	}
+	w.cells = alive
+}
+
+func (w *World) resetTiles() {
+	for i := range w.tiles {
+		for j := range w.tiles[i] {
+			w.tiles[i][j].ResetCellCount()
+		}
+	}
+}
+
+// tileIndex returns the tile coordinates of a given position, clamped to the grid.
+func (w *World) tileIndex(pos vector.Vector2D) (x, y int) {
+	clamp := func(i, n int) int {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+	x = clamp(int(math.Floor(pos.X/float64(w.TileDimension))), len(w.tiles))
+	y = clamp(int(math.Floor(pos.Y/float64(w.TileDimension))), len(w.tiles[x]))
+	return x, y
+}
+
+// indexCells rebuilds the spatial index from the current cell and pellet positions.
+func (w *World) indexCells() {
+	w.resetTiles()
+	for _, c := range w.cells {
+		x, y := w.tileIndex(c.Position())
+		w.tiles[x][y].AddCell(c)
+	}
+	for _, p := range w.pellets {
+		x, y := w.tileIndex(p.Position)
+		w.tiles[x][y].AddFood(p)
+	}
+}
+
+// Step advances the world by one tick.
+// It returns an error when all cells are dead and the spawner is disabled. An enabled spawner, even with
+// only a rate set, generates a cell whenever the population drops to zero, so that Step never fails.
+func (w *World) Step() error {
+	w.counter++
+	w.removeDeadCells()
+	w.spawn()
+	if len(w.cells) == 0 {
+		return fmt.Errorf("all cells are dead")
+	}
+	w.growFood()
+	w.indexCells()
+
+	// cells are updated in a stable order for runs to be reproducible
+	newborns := []*cell.Cell{}
+	for _, c := range w.cells {
+		// a cell may have been eaten earlier in this tick
+		if c.IsDead() {
+			continue
+		}
+		// update cell state
+		newborns = append(newborns, c.Update(w.counter)...)
+		w.notifyMeals(c)
+	}
+	// newborns join the world once every cell has been updated
+	w.cells = append(w.cells, newborns...)
+	for _, c := range newborns {
+		w.notifyBirth(c)
+	}
+
+	if w.config.Collisions {
+		w.resolveCollisions()
+	}
+	w.removeDeadCells()
+	w.removeEatenFood()
+	return nil
+}
+
+// resolveCollisions pushes apart the living cells overlapping each other,
+// unless one of them is able to eat the other.
+func (w *World) resolveCollisions() {
+	// cells moved and newborns joined since the tiles were indexed
+	w.indexCells()
+	maxSize := 0.0
+	for _, c := range w.cells {
+		maxSize = math.Max(maxSize, c.Size())
+	}
+	for _, c := range w.cells {
+		if c.IsDead() {
+			continue
+		}
+		for _, c2 := range w.Detect(c.Position(), c.Size()+maxSize) {
+			if c2 != c && c.Intersect(c2) && !c.CanEat(c2) && !c2.CanEat(c) {
+				c.Collide(c2)
+			}
+		}
+	}
+}
+
+// Config returns the world parameters.
+func (w *World) Config() Config {
+	return w.config
+}
+
+// Counter returns the number of ticks elapsed since the world creation.
+func (w *World) Counter() int {
+	return w.counter
+}
+
+// Seed returns the seed of the world random source.
+func (w *World) Seed() int64 {
+	return w.src.seed
+}
+
+// Cells returns the cells living in the world.
+func (w *World) Cells() []*cell.Cell {
+	return w.cells
+}
+
+// Detect returns all cells located in a radius from (x,y), the world being a torus.
+// Only the tiles overlapping the radius are scanned.
+func (w *World) Detect(pos vector.Vector2D, radius float64) []*cell.Cell {
+	nearestCells := []*cell.Cell{}
+	width, height := float64(w.Width), float64(w.Height)
+
+	for _, i := range w.tileSpan(pos.X, radius, width, len(w.tiles)) {
+		for _, j := range w.tileSpan(pos.Y, radius, height, len(w.tiles[i])) {
+			for _, c := range w.tiles[i][j].Cells() {
+				if !c.IsDead() && pos.TorusSquareDistance(c.Position(), width, height) < radius*radius {
+					nearestCells = append(nearestCells, c)
+				}
+			}
+		}
+	}
+
+	return nearestCells
+}
+
+// tileSpan returns the indexes of the n tiles overlapping [x-radius, x+radius] along an axis of a given size,
+// wrapping around the world edges.
+func (w *World) tileSpan(x, radius, size float64, n int) []int {
+	index := func(v float64) int {
+		i := int(math.Floor(v / float64(w.TileDimension)))
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+	span := []int{}
+	seen := make([]bool, n)
+	add := func(lo, hi float64) {
+		for i := index(lo); i <= index(hi); i++ {
+			if !seen[i] {
+				seen[i] = true
+				span = append(span, i)
+			}
+		}
+	}
+
+	lo, hi := x-radius, x+radius
+	switch {
+	case hi-lo >= size:
+		add(0, size)
+	case lo < 0:
+		add(lo+size, size)
+		add(0, hi)
+	case hi >= size:
+		add(lo, size)
+		add(0, hi-size)
+	default:
+		add(lo, hi)
+	}
+	return span
+}
+
+// Population returns the number of living cells.
+func (w *World) Population() int {
+	n := 0
+	for _, c := range w.cells {
+		if !c.IsDead() {
+			n++
+		}
+	}
+	return n
+}
+
+// Tiles returns the tiles grid covering the world.
+func (w *World) Tiles() [][]*Tile {
+	return w.tiles
+}
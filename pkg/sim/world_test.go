@@ -0,0 +1,56 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testTicks is the number of ticks worlds run for in tests, long enough for cells to eat, divide and die.
+const testTicks = 500
+
+// testConfig returns the parameters of the worlds run by tests, with cells driven by a brain
+// for tests to cover their networks.
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Width, config.Height = 640, 480
+	config.Cell.Brain.Hidden = []int{6}
+	return config
+}
+
+func step(t *testing.T, w *World, ticks int) {
+	t.Helper()
+	for i := 0; i < ticks; i++ {
+		if err := w.Step(); err != nil {
+			t.Fatalf("step %d: %s", w.Counter(), err)
+		}
+	}
+}
+
+// state describes the cells of a world, for two worlds to be compared.
+func state(w *World) string {
+	var b strings.Builder
+	for _, c := range w.Cells() {
+		fmt.Fprintf(&b, "%s %v %v %v %v %v\n", c.ID(), c.Position(), c.Velocity(), c.Orientation(), c.Size(), c.Energy())
+	}
+	return b.String()
+}
+
+func TestSameSeedSameState(t *testing.T) {
+	a := New(testConfig(), 42)
+	b := New(testConfig(), 42)
+	if state(a) != state(b) {
+		t.Fatal("initial worlds of the same seed differ")
+	}
+	step(t, a, testTicks)
+	step(t, b, testTicks)
+	if state(a) != state(b) {
+		t.Errorf("worlds of the same seed differ after %d ticks", testTicks)
+	}
+
+	c := New(testConfig(), 43)
+	step(t, c, testTicks)
+	if state(a) == state(c) {
+		t.Errorf("worlds of different seeds are identical after %d ticks", testTicks)
+	}
+}
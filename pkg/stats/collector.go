@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+// Format is a statistics export format.
+type Format int
+
+const (
+	// CSV writes a header, then a row per sample.
+	CSV Format = iota
+	// JSONLines writes a JSON object per line and per sample.
+	JSONLines
+)
+
+// FormatFromPath returns the format of a statistics file from its extension,
+// CSV for ".csv" files and JSON lines otherwise.
+func FormatFromPath(path string) Format {
+	if filepath.Ext(path) == ".csv" {
+		return CSV
+	}
+	return JSONLines
+}
+
+// Collector records a sample of a world population every given number of ticks.
+// It must be registered as an observer of the world to count births and deaths.
+type Collector struct {
+	every  int
+	format Format
+	csv    *csv.Writer
+	json   *json.Encoder
+	header bool
+	births int
+	deaths map[cell.DeathCause]int
+}
+
+// NewCollector creates a collector writing a sample every given number of ticks.
+func NewCollector(w io.Writer, format Format, every int) *Collector {
+	c := &Collector{
+		every:  every,
+		format: format,
+		deaths: map[cell.DeathCause]int{},
+	}
+	if format == CSV {
+		c.csv = csv.NewWriter(w)
+	} else {
+		c.json = json.NewEncoder(w)
+	}
+	return c
+}
+
+// Born counts a birth.
+func (c *Collector) Born(w *sim.World, _ *cell.Cell) {
+	c.births++
+}
+
+// Died counts a death by cause.
+func (c *Collector) Died(w *sim.World, dead *cell.Cell) {
+	c.deaths[dead.DeathCause()]++
+}
+
+// Collect writes a sample if the world tick is a multiple of the sampling period.
+func (c *Collector) Collect(w *sim.World) error {
+	if c.every <= 0 || w.Counter()%c.every != 0 {
+		return nil
+	}
+	s := Measure(w.Counter(), w.Cells())
+	s.Births = c.births
+	s.Deaths = c.deaths
+	c.births = 0
+	c.deaths = map[cell.DeathCause]int{}
+	return c.write(s)
+}
+
+func (c *Collector) write(s Sample) error {
+	if c.format == JSONLines {
+		return c.json.Encode(s)
+	}
+	if !c.header {
+		header := []string{"tick", "population"}
+		for _, diet := range cell.Diets {
+			header = append(header, "population_"+string(diet))
+		}
+		header = append(header, "births")
+		for _, cause := range cell.DeathCauses {
+			header = append(header, "deaths_"+string(cause))
+		}
+		for _, name := range []string{"size", "energy", "speed", "detection_radius"} {
+			header = append(header, name+"_mean", name+"_variance")
+		}
+		if err := c.csv.Write(header); err != nil {
+			return err
+		}
+		c.header = true
+	}
+	row := []string{strconv.Itoa(s.Tick), strconv.Itoa(s.Population)}
+	for _, diet := range cell.Diets {
+		row = append(row, strconv.Itoa(s.Diets[diet]))
+	}
+	row = append(row, strconv.Itoa(s.Births))
+	for _, cause := range cell.DeathCauses {
+		row = append(row, strconv.Itoa(s.Deaths[cause]))
+	}
+	for _, m := range []Moments{s.Size, s.Energy, s.Speed, s.DetectionRadius} {
+		row = append(row, formatFloat(m.Mean), formatFloat(m.Variance))
+	}
+	if err := c.csv.Write(row); err != nil {
+		return err
+	}
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+	"github.com/jtbonhomme/golife/pkg/sim"
+)
+
+const (
+	testTicks = 300
+	testEvery = 50
+)
+
+// birthCounter counts all the births of a world.
+type birthCounter int
+
+func (b *birthCounter) Born(*sim.World, *cell.Cell) { *b++ }
+
+func (b *birthCounter) Died(*sim.World, *cell.Cell) {}
+
+// collect runs a world with a collector writing its samples in a given format to buf. It checks the collector
+// flushes each sample as soon as it is collected and returns the number of births.
+func collect(t *testing.T, buf *bytes.Buffer, format Format) int {
+	t.Helper()
+	config := sim.DefaultConfig()
+	config.Width, config.Height = 640, 480
+	// births between samples
+	config.Spawner.Rate = 0.1
+	w := sim.New(config, 11)
+	c := NewCollector(buf, format, testEvery)
+	births := new(birthCounter)
+	w.AddObserver(c)
+	w.AddObserver(births)
+	for i := 0; i < testTicks; i++ {
+		if err := w.Step(); err != nil {
+			t.Fatal(err)
+		}
+		written := buf.Len()
+		if err := c.Collect(w); err != nil {
+			t.Fatal(err)
+		}
+		sampled := w.Counter()%testEvery == 0
+		if sampled != (buf.Len() > written) {
+			t.Fatalf("tick %d: %d bytes written", w.Counter(), buf.Len()-written)
+		}
+		if sampled && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			t.Fatalf("tick %d: sample not flushed", w.Counter())
+		}
+	}
+	return int(*births)
+}
+
+func TestCollectCSV(t *testing.T) {
+	var buf bytes.Buffer
+	births := collect(t, &buf, CSV)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1+testTicks/testEvery {
+		t.Fatalf("%d rows, expected a header and %d samples", len(rows), testTicks/testEvery)
+	}
+	expected := []string{"tick", "population", "population_herbivore", "population_carnivore", "population_omnivore", "births"}
+	for i, name := range expected {
+		if rows[0][i] != name {
+			t.Fatalf("header %v, expected it to start with %v", rows[0], expected)
+		}
+	}
+	if rows[0][len(rows[0])-1] != "detection_radius_variance" {
+		t.Errorf("header %v, expected it to end with detection_radius_variance", rows[0])
+	}
+	total := 0
+	for i, row := range rows[1:] {
+		if len(row) != len(rows[0]) {
+			t.Fatalf("row %v has %d columns, header %d", row, len(row), len(rows[0]))
+		}
+		if tick, _ := strconv.Atoi(row[0]); tick != (i+1)*testEvery {
+			t.Errorf("row %d at tick %s, expected %d", i, row[0], (i+1)*testEvery)
+		}
+		population, _ := strconv.Atoi(row[1])
+		diets := 0
+		for _, col := range row[2:5] {
+			n, _ := strconv.Atoi(col)
+			diets += n
+		}
+		if diets != population {
+			t.Errorf("row %d: %d cells among diets, population %d", i, diets, population)
+		}
+		n, _ := strconv.Atoi(row[5])
+		total += n
+	}
+	if total != births || births == 0 {
+		t.Errorf("%d births sampled, expected %d", total, births)
+	}
+}
+
+func TestCollectJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	births := collect(t, &buf, JSONLines)
+	scanner := bufio.NewScanner(&buf)
+	samples := []Sample{}
+	for scanner.Scan() {
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatalf("line %q: %s", scanner.Text(), err)
+		}
+		samples = append(samples, s)
+	}
+	if len(samples) != testTicks/testEvery {
+		t.Fatalf("%d samples, expected %d", len(samples), testTicks/testEvery)
+	}
+	total := 0
+	for i, s := range samples {
+		if s.Tick != (i+1)*testEvery {
+			t.Errorf("sample %d at tick %d, expected %d", i, s.Tick, (i+1)*testEvery)
+		}
+		diets := 0
+		for _, n := range s.Diets {
+			diets += n
+		}
+		if diets != s.Population {
+			t.Errorf("sample %d: %d cells among diets, population %d", i, diets, s.Population)
+		}
+		total += s.Births
+	}
+	if total != births || births == 0 {
+		t.Errorf("%d births sampled, expected %d", total, births)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, f := range map[string]Format{"stats.csv": CSV, "stats.jsonl": JSONLines, "stats": JSONLines} {
+		if FormatFromPath(path) != f {
+			t.Errorf("%s has format %d, expected %d", path, FormatFromPath(path), f)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/golife/pkg/cell"
+)
+
+// Moments are the mean and variance of a cell property over a population.
+type Moments struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+}
+
+// Sample describes a population at a given tick.
+type Sample struct {
+	Tick       int `json:"tick"`
+	Population int `json:"population"`
+	// Diets is the population of each diet.
+	Diets map[cell.Diet]int `json:"diets"`
+	// Births and Deaths are counted since the previous sample.
+	Births          int                     `json:"births"`
+	Deaths          map[cell.DeathCause]int `json:"deaths"`
+	Size            Moments                 `json:"size"`
+	Energy          Moments                 `json:"energy"`
+	Speed           Moments                 `json:"speed"`
+	DetectionRadius Moments                 `json:"detectionRadius"`
+}
+
+// moments computes the mean and variance of values.
+func moments(values []float64) Moments {
+	if len(values) == 0 {
+		return Moments{}
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+	return Moments{Mean: mean, Variance: variance}
+}
+
+// Measure returns the sample of the living cells, births and deaths excluded.
+func Measure(tick int, cells []*cell.Cell) Sample {
+	var sizes, energies, speeds, radii []float64
+	diets := map[cell.Diet]int{}
+	for _, c := range cells {
+		if c.IsDead() {
+			continue
+		}
+		diets[c.Diet()]++
+		velocity := c.Velocity()
+		sizes = append(sizes, c.Size())
+		energies = append(energies, c.Energy())
+		speeds = append(speeds, math.Sqrt(velocity.MagnitudeSquared()))
+		radii = append(radii, c.DetectionRadius())
+	}
+	return Sample{
+		Tick:            tick,
+		Population:      len(sizes),
+		Diets:           diets,
+		Deaths:          map[cell.DeathCause]int{},
+		Size:            moments(sizes),
+		Energy:          moments(energies),
+		Speed:           moments(speeds),
+		DetectionRadius: moments(radii),
+	}
+}